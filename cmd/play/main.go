@@ -0,0 +1,82 @@
+// Command play plays a game of Tic-Tac-Turing in the terminal, against the
+// built-in engine or an LLM champion reached through an OpenAI-compatible
+// chat completions API. It uses the same prompt as the MCP server, so it is
+// a quick way to try out prompts and heckles without an MCP host.
+//
+//	play -opponent engine
+//	OPENAI_API_KEY=... play -model gpt-4o-mini
+//	play -base-url http://localhost:11434/v1 -model llama3.2 -system prompt.txt
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ggoodman/tic-tac-turing/internal/champion"
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+)
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	opponent := flag.String("opponent", "llm", "who plays the champion: llm or engine")
+	baseURL := flag.String("base-url", envOr("OPENAI_BASE_URL", "https://api.openai.com/v1"), "OpenAI-compatible API root")
+	model := flag.String("model", os.Getenv("OPENAI_MODEL"), "model to play the champion")
+	system := flag.String("system", "", "file holding a system prompt to use instead of the server's")
+	position := flag.String("position", "", "starting position, in any form accepted by start_game")
+	flag.Parse()
+
+	var opp champion.Opponent
+	switch *opponent {
+	case "engine":
+		opp = champion.Engine{}
+	case "llm":
+		if *model == "" {
+			fatalf("-model (or OPENAI_MODEL) is required to play an LLM")
+		}
+		var prompt string
+		if *system != "" {
+			b, err := os.ReadFile(*system)
+			if err != nil {
+				fatalf("error reading system prompt: %v", err)
+			}
+			prompt = string(b)
+		}
+		opp = champion.NewLLM(&champion.OpenAIProvider{
+			BaseURL: *baseURL,
+			APIKey:  os.Getenv("OPENAI_API_KEY"),
+			Model:   *model,
+		}, prompt)
+	default:
+		fatalf("unknown opponent %q", *opponent)
+	}
+
+	gs, err := ticktacktoe.ParsePosition(*position)
+	if err != nil {
+		fatalf("invalid starting position: %v", err)
+	}
+	if gs.PlayerToMove() != 'X' {
+		fatalf("the starting position must be unfinished with X to move")
+	}
+
+	if err := play(ctx, os.Stdin, os.Stdout, opp, gs); err != nil {
+		fatalf("%v", err)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "play: "+format+"\n", args...)
+	os.Exit(1)
+}
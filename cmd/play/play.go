@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ggoodman/tic-tac-turing/internal/archive"
+	"github.com/ggoodman/tic-tac-turing/internal/champion"
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+)
+
+// play runs a game from gs, with X to move, reading the human's moves and
+// heckles from in. It ends with a review of the game, or early with an error
+// when in runs dry or the champion cannot move.
+func play(ctx context.Context, in io.Reader, out io.Writer, opp champion.Opponent, gs *ticktacktoe.GameState) error {
+	lines := bufio.NewScanner(in)
+	ask := func(prompt string) (string, error) {
+		fmt.Fprint(out, prompt)
+		if !lines.Scan() {
+			if err := lines.Err(); err != nil {
+				return "", err
+			}
+			return "", errors.New("no more input; game abandoned")
+		}
+		return strings.TrimSpace(lines.Text()), nil
+	}
+
+	setUp := len(gs.ToString())
+	game := &archive.Game{SetUpPlies: setUp, Heckles: make([]string, setUp/2)}
+
+	for gs.PlayerToMove() != 0 {
+		fmt.Fprintf(out, "\n%s\n", gs.BoardString())
+
+		var move string
+		for {
+			answer, err := ask("Your move (A1-C3): ")
+			if err != nil {
+				return err
+			}
+			move = strings.ToUpper(answer)
+			square, err := ticktacktoe.GridToSquare(move)
+			if err == nil {
+				err = gs.ApplyMove(square)
+			}
+			if err == nil {
+				break
+			}
+			fmt.Fprintf(out, "Invalid move: %v\n", err)
+		}
+		heckle, err := ask("Heckle (optional): ")
+		if err != nil {
+			return err
+		}
+		game.Heckles = append(game.Heckles, heckle)
+		if gs.PlayerToMove() == 0 {
+			break
+		}
+
+		square, model, err := opp.Move(ctx, gs, move, heckle)
+		if err != nil {
+			return fmt.Errorf("error getting the champion's move: %w", err)
+		}
+		if err := gs.ApplyMove(square); err != nil {
+			return fmt.Errorf("error playing the champion's move: %w", err)
+		}
+		game.Model = model
+		grid, _ := ticktacktoe.SquareToGrid(square)
+		fmt.Fprintf(out, "The champion (%s) plays %s.\n", model, grid)
+	}
+
+	fmt.Fprintf(out, "\n%s\n", gs.BoardString())
+	switch gs.Winner() {
+	case 'X':
+		fmt.Fprintln(out, "You defeated the champion! The Tic-Tac-Turing test is still alive.")
+	case 'O':
+		fmt.Fprintln(out, "The champion wins. Have you never played Tic-Tac-Turing before?!")
+	default:
+		fmt.Fprintln(out, "A draw. The champion holds on.")
+	}
+
+	game.Moves = gs.ToString()
+	if review, err := archive.NewReview(game); err == nil {
+		fmt.Fprintf(out, "\n%s", review)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ggoodman/tic-tac-turing/internal/champion"
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+)
+
+func TestPlayAgainstScriptedChampion(t *testing.T) {
+	in := strings.NewReader("b2\nLook behind you\nZ9\nA1\n\nC3\ngg\n")
+	p := champion.NewScripted("B1", "A2")
+	var out strings.Builder
+
+	if err := play(context.Background(), in, &out, champion.NewLLM(p, ""), ticktacktoe.NewGameState()); err != nil {
+		t.Fatal(err)
+	}
+
+	text := out.String()
+	for _, want := range []string{
+		"Invalid move",
+		"The champion (scripted) plays B1.",
+		"The champion (scripted) plays A2.",
+		"You defeated the champion!",
+		"## Game review",
+		"It followed the heckle “Look behind you”.",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, text)
+		}
+	}
+	if prompts := p.Prompts(); len(prompts) != 2 || !strings.Contains(prompts[0], "User heckle: Look behind you") {
+		t.Fatalf("expected the heckle to reach the champion, got %q", prompts)
+	}
+}
+
+func TestPlayStopsWhenInputEnds(t *testing.T) {
+	var out strings.Builder
+	err := play(context.Background(), strings.NewReader("B2\n\n"), &out, champion.Engine{}, ticktacktoe.NewGameState())
+	if err == nil || !strings.Contains(err.Error(), "abandoned") {
+		t.Fatalf("expected the game to be abandoned, got %v", err)
+	}
+	if !strings.Contains(out.String(), "The champion (engine) plays") {
+		t.Fatalf("expected the engine to reply, got:\n%s", out.String())
+	}
+}
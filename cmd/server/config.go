@@ -1,8 +1,32 @@
 package main
 
+import (
+	"time"
+
+	"github.com/ggoodman/tic-tac-turing/internal/ratelimit"
+)
+
 type Config struct {
-	Port          int    `env:"PORT,default=8080"`
-	PublicUrl     string `env:"PUBLIC_URL,default=http://localhost:8080"`
-	RedisUrl      string `env:"REDIS_URL,default=redis://localhost:6379"`
-	AuthIssuerUrl string `env:"AUTH_ISSUER_URL,default=http://localhost:8081"`
+	Port               int            `env:"PORT,default=8080"`
+	PublicUrl          string         `env:"PUBLIC_URL,default=http://localhost:8080"`
+	Storage            string         `env:"STORAGE,default=redis"`
+	RedisUrl           string         `env:"REDIS_URL,default=redis://localhost:6379"`
+	AuthMode           string         `env:"AUTH_MODE,default=oidc"`
+	AuthIssuerUrl      string         `env:"AUTH_ISSUER_URL,default=http://localhost:8081"`
+	AuthExtraAudiences []string       `env:"AUTH_EXTRA_AUDIENCES,default=https://tic-tac-turing.fly.dev/mcp"`
+	AbandonAfter       time.Duration  `env:"ABANDON_AFTER,default=30m"`
+	UserGamesTTL       time.Duration  `env:"USER_GAMES_TTL,default=168h"`
+	TraceExporter      string         `env:"TRACE_EXPORTER,default=none"`
+	PseudonymKey       string         `env:"PSEUDONYM_KEY"`
+	ClientIPHeader     string         `env:"CLIENT_IP_HEADER"`
+	StartGameLimit     ratelimit.Rate `env:"START_GAME_LIMIT,default=30/h"`
+	StartGameIPLimit   ratelimit.Rate `env:"START_GAME_IP_LIMIT,default=120/h"`
+	TakeTurnLimit      ratelimit.Rate `env:"TAKE_TURN_LIMIT,default=300/h"`
+	TakeTurnIPLimit    ratelimit.Rate `env:"TAKE_TURN_IP_LIMIT,default=1200/h"`
+	SamplingBudget     ratelimit.Rate `env:"SAMPLING_BUDGET,default=1000/24h"`
+	ReadTimeout        time.Duration  `env:"READ_TIMEOUT,default=30s"`
+	WriteTimeout       time.Duration  `env:"WRITE_TIMEOUT,default=0"`
+	IdleTimeout        time.Duration  `env:"IDLE_TIMEOUT,default=2m"`
+	ElicitationTimeout time.Duration  `env:"ELICITATION_TIMEOUT,default=5m"`
+	SamplingTimeout    time.Duration  `env:"SAMPLING_TIMEOUT,default=1m"`
 }
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// readinessTimeout bounds each readiness check.
+const readinessTimeout = 5 * time.Second
+
+// readinessCheck is a dependency that must be reachable for the server to
+// take traffic.
+type readinessCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// healthzHandler reports that the process is up. It checks nothing else so
+// that a slow dependency never gets the machine restarted.
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte("ok\n"))
+}
+
+type readyzResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// readyzHandler runs every check and answers 503 when any of them fails,
+// naming the failures.
+func readyzHandler(checks []readinessCheck) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res := readyzResponse{Status: "ok", Checks: map[string]string{}}
+		status := http.StatusOK
+		for _, c := range checks {
+			ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+			err := c.check(ctx)
+			cancel()
+			if err != nil {
+				res.Checks[c.name] = err.Error()
+				res.Status = "unavailable"
+				status = http.StatusServiceUnavailable
+				continue
+			}
+			res.Checks[c.name] = "ok"
+		}
+		writeJSON(w, status, res)
+	}
+}
+
+// discoveryCheck checks that the OIDC issuer still serves its discovery
+// document, which the authenticator needs to refresh signing keys.
+func discoveryCheck(issuerUrl string) func(ctx context.Context) error {
+	url := strings.TrimSuffix(issuerUrl, "/") + "/.well-known/openid-configuration"
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return fmt.Errorf("discovery document returned status %d", res.StatusCode)
+		}
+		return nil
+	}
+}
+
+type versionResponse struct {
+	Version   string `json:"version"`
+	Revision  string `json:"revision,omitempty"`
+	Time      string `json:"time,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"goVersion"`
+}
+
+// versionHandler reports the build the server was compiled from.
+func versionHandler(w http.ResponseWriter, r *http.Request) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		http.Error(w, "build information unavailable", http.StatusInternalServerError)
+		return
+	}
+	res := versionResponse{Version: info.Main.Version, GoVersion: info.GoVersion}
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			res.Revision = s.Value
+		case "vcs.time":
+			res.Time = s.Value
+		case "vcs.modified":
+			res.Modified = s.Value == "true"
+		}
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadyz(t *testing.T) {
+	issuer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/openid-configuration" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer issuer.Close()
+
+	redisErr := errors.New("connection refused")
+	checks := []readinessCheck{
+		{name: "redis", check: func(ctx context.Context) error { return redisErr }},
+		{name: "auth", check: discoveryCheck(issuer.URL + "/")},
+	}
+
+	get := func() (int, readyzResponse) {
+		rec := httptest.NewRecorder()
+		readyzHandler(checks)(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		var res readyzResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		return rec.Code, res
+	}
+
+	code, res := get()
+	if code != http.StatusServiceUnavailable || res.Checks["redis"] != "connection refused" || res.Checks["auth"] != "ok" {
+		t.Fatalf("expected redis to fail the readiness check, got %d %+v", code, res)
+	}
+
+	redisErr = nil
+	if code, res := get(); code != http.StatusOK || res.Status != "ok" {
+		t.Fatalf("expected the server to be ready, got %d %+v", code, res)
+	}
+
+	issuer.Close()
+	if code, res := get(); code != http.StatusServiceUnavailable || res.Checks["auth"] == "ok" {
+		t.Fatalf("expected an unreachable issuer to fail the readiness check, got %d %+v", code, res)
+	}
+}
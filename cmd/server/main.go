@@ -10,9 +10,23 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/ggoodman/mcp-server-go/auth"
+	"github.com/ggoodman/mcp-server-go/sessions"
+	"github.com/ggoodman/mcp-server-go/sessions/redishost"
+	"github.com/ggoodman/tic-tac-turing/internal/archive"
+	"github.com/ggoodman/tic-tac-turing/internal/idle"
 	"github.com/ggoodman/tic-tac-turing/internal/mcp"
+	"github.com/ggoodman/tic-tac-turing/internal/memhost"
+	"github.com/ggoodman/tic-tac-turing/internal/metrics"
+	"github.com/ggoodman/tic-tac-turing/internal/pseudonym"
+	"github.com/ggoodman/tic-tac-turing/internal/rating"
+	"github.com/ggoodman/tic-tac-turing/internal/userdata"
 	"github.com/ggoodman/tic-tac-turing/internal/web"
 	"github.com/joeshaw/envdecode"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
 )
 
 func main() {
@@ -31,9 +45,110 @@ func main() {
 	// Initialize web content (parses markdown at startup)
 	web.Init()
 
+	var (
+		host      sessions.SessionHost
+		games     archive.Store
+		userGames userdata.Store
+		stats     userdata.Store
+		ratings   rating.Store
+		idleGames idle.Store
+		checks    []readinessCheck
+	)
+	switch cfg.Storage {
+	case "redis":
+		redisOpts, err := redis.ParseURL(cfg.RedisUrl)
+		if err != nil {
+			log.ErrorContext(ctx, "failed to parse redis url", slog.String("err", err.Error()))
+			os.Exit(1)
+		}
+		redisClient := redis.NewClient(redisOpts)
+		defer redisClient.Close()
+
+		host, err = redishost.New(cfg.RedisUrl, redishost.WithKeyPrefix("tic-tac-turing:"))
+		if err != nil {
+			log.ErrorContext(ctx, "failed to create redis session host", slog.String("err", err.Error()))
+			os.Exit(1)
+		}
+		games = archive.NewRedisStore(redisClient, "tic-tac-turing:")
+		userGames = userdata.NewRedisStore(redisClient, "tic-tac-turing:", cfg.UserGamesTTL)
+		stats = userdata.NewRedisStore(redisClient, "tic-tac-turing:stats:", 0)
+		ratings = rating.NewRedisStore(redisClient, "tic-tac-turing:")
+		idleGames = idle.NewRedisStore(redisClient, "tic-tac-turing:")
+		checks = append(checks, readinessCheck{name: "redis", check: func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		}})
+	case "memory":
+		// Everything lives in this process and is lost when it exits.
+		log.WarnContext(ctx, "using in-memory storage; sessions and games will not survive a restart")
+		host = memhost.New()
+		games = archive.NewMemoryStore()
+		userGames = userdata.NewMemoryStore()
+		stats = userdata.NewMemoryStore()
+		ratings = rating.NewMemoryStore()
+		idleGames = idle.NewMemoryStore()
+	default:
+		log.ErrorContext(ctx, "unknown storage backend", slog.String("storage", cfg.Storage))
+		os.Exit(1)
+	}
+
+	if cfg.PseudonymKey == "" {
+		log.WarnContext(ctx, "PSEUDONYM_KEY is not set; public player names are unkeyed hashes of user IDs")
+	}
+	names := pseudonym.New([]byte(cfg.PseudonymKey))
+
 	mcpUrl := cfg.PublicUrl + "/mcp"
 
-	mcpHandler, err := mcp.NewTicTacTuringHandler(ctx, log, mcpUrl, cfg.AuthIssuerUrl, cfg.RedisUrl)
+	var authenticator auth.Authenticator
+	switch cfg.AuthMode {
+	case "oidc":
+		var authOpts []auth.AccessTokenAuthOption
+		for _, aud := range cfg.AuthExtraAudiences {
+			authOpts = append(authOpts, auth.WithExtraAudience(aud))
+		}
+		provider, err := auth.NewFromDiscovery(ctx, cfg.AuthIssuerUrl, mcpUrl, authOpts...)
+		if err != nil {
+			log.ErrorContext(ctx, "failed to configure auth", slog.String("err", err.Error()))
+			os.Exit(1)
+		}
+		authenticator = provider
+		checks = append(checks, readinessCheck{name: "auth", check: discoveryCheck(cfg.AuthIssuerUrl)})
+	case "none":
+		log.WarnContext(ctx, "authentication is disabled; any bearer token is accepted as a user ID")
+	default:
+		log.ErrorContext(ctx, "unknown auth mode", slog.String("auth_mode", cfg.AuthMode))
+		os.Exit(1)
+	}
+
+	shutdownTracing, err := setupTracing(ctx, cfg.TraceExporter)
+	if err != nil {
+		log.ErrorContext(ctx, "failed to configure tracing", slog.String("err", err.Error()))
+		os.Exit(1)
+	}
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	mcpHandler, err := mcp.NewTicTacTuringHandler(ctx, log, mcpUrl, host, authenticator,
+		mcp.WithGameArchive(games, cfg.PublicUrl),
+		mcp.WithAbandonAfter(cfg.AbandonAfter),
+		mcp.WithIdleGames(idleGames),
+		mcp.WithUserGames(userGames),
+		mcp.WithPlayerStats(stats),
+		mcp.WithRatings(ratings, names),
+		mcp.WithMetrics(metrics.New(reg)),
+		mcp.WithClientIPHeader(cfg.ClientIPHeader),
+		mcp.WithTurnDeadlines(cfg.ElicitationTimeout, cfg.SamplingTimeout),
+		mcp.WithLimits(mcp.Limits{
+			StartGamePerUser: cfg.StartGameLimit,
+			StartGamePerIP:   cfg.StartGameIPLimit,
+			TakeTurnPerUser:  cfg.TakeTurnLimit,
+			TakeTurnPerIP:    cfg.TakeTurnIPLimit,
+			SamplingBudget:   cfg.SamplingBudget,
+		}),
+	)
 	if err != nil {
 		log.ErrorContext(ctx, "failed to create MCP handler", slog.String("err", err.Error()))
 		os.Exit(1)
@@ -59,16 +174,33 @@ func main() {
 	mux.HandleFunc("GET /home.md", web.Handler)
 	mux.HandleFunc("GET /index.md", web.Handler)
 
+	// Shareable pages and social cards for finished games
+	gamePages := web.NewGamePages(games, names, cfg.PublicUrl)
+	mux.HandleFunc("GET /games/{id}", gamePages.GameHandler)
+	mux.HandleFunc("GET /games/{id}/card.png", gamePages.CardHandler)
+	mux.HandleFunc("GET /games/{id}/record", gamePages.RecordHandler)
+
+	// Leaderboards of players and champions
+	mux.HandleFunc("GET /ratings", web.NewRatingsPage(ratings, names, cfg.PublicUrl).Handler)
+
+	// Health checks and build info, ahead of the MCP catch-all
+	mux.HandleFunc("GET /healthz", healthzHandler)
+	mux.HandleFunc("GET /readyz", readyzHandler(checks))
+	mux.HandleFunc("GET /version", versionHandler)
+
+	// Prometheus metrics
+	mux.Handle("GET /metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+
 	// Register MCP handler as fallback - handles /mcp and .well-known paths
 	mux.Handle("/", mcpHandler)
 
 	// Create server
 	srv := &http.Server{
-		Addr:    fmt.Sprintf(":%d", cfg.Port),
-		Handler: mux,
-		// ReadTimeout:  15 * time.Second,
-		// WriteTimeout: 15 * time.Second,
-		// IdleTimeout:  60 * time.Second,
+		Addr:         fmt.Sprintf(":%d", cfg.Port),
+		Handler:      mux,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
 	}
 
 	// Start server in a goroutine
@@ -92,5 +224,9 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.ErrorContext(ctx, "failed to flush traces", slog.String("err", err.Error()))
+	}
+
 	log.InfoContext(ctx, "server exited properly")
 }
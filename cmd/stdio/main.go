@@ -0,0 +1,66 @@
+// Command stdio serves Tic-Tac-Turing over stdin/stdout so that desktop MCP
+// hosts can run it as a local server, without OAuth, Redis or a public URL.
+// Games in progress and puzzle streaks are kept in files under DATA_DIR.
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/ggoodman/mcp-server-go/mcpservice"
+	"github.com/ggoodman/mcp-server-go/stdio"
+	"github.com/ggoodman/tic-tac-turing/internal/mcp"
+	"github.com/ggoodman/tic-tac-turing/internal/userdata"
+	"github.com/joeshaw/envdecode"
+)
+
+type Config struct {
+	DataDir      string        `env:"DATA_DIR"`
+	AbandonAfter time.Duration `env:"ABANDON_AFTER,default=0"`
+}
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	// Stdout carries the protocol, so logs go to stderr where hosts collect them.
+	log := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	slog.SetDefault(log)
+
+	var cfg Config
+
+	if err := envdecode.Decode(&cfg); err != nil {
+		log.ErrorContext(ctx, "failed to decode config from environment", slog.String("err", err.Error()))
+		os.Exit(1)
+	}
+
+	if cfg.DataDir == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			log.ErrorContext(ctx, "failed to find a data directory; set DATA_DIR", slog.String("err", err.Error()))
+			os.Exit(1)
+		}
+		cfg.DataDir = filepath.Join(dir, "tic-tac-turing")
+	}
+
+	log.InfoContext(ctx, "serving over stdio", slog.String("data_dir", cfg.DataDir))
+	if err := stdio.NewHandler(newServer(cfg), stdio.WithLogger(log)).Serve(ctx); err != nil && ctx.Err() == nil {
+		log.ErrorContext(ctx, "error serving over stdio", slog.String("err", err.Error()))
+		os.Exit(1)
+	}
+}
+
+// newServer keeps games by user in files under cfg.DataDir. The stdio
+// handler names its only user after the local account (user-<uid>), so every
+// run by the same account picks up the same games.
+func newServer(cfg Config) mcpservice.ServerCapabilities {
+	return mcp.NewTickTackTuringServer(
+		mcp.WithAbandonAfter(cfg.AbandonAfter),
+		mcp.WithUserGames(userdata.NewFileStore(cfg.DataDir)),
+	)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ggoodman/mcp-server-go/stdio"
+)
+
+// stdioClient speaks JSON-RPC to a server over in-process pipes, the way a
+// desktop host speaks to the command over stdin and stdout.
+type stdioClient struct {
+	t      *testing.T
+	stdin  io.Writer
+	stdout *bufio.Scanner
+	nextID int
+}
+
+func startServer(t *testing.T, cfg Config) *stdioClient {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h := stdio.NewHandler(newServer(cfg), stdio.WithIO(inR, outW), stdio.WithLogger(slog.New(slog.DiscardHandler)))
+		_ = h.Serve(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		inW.Close()
+		outR.Close()
+		<-done
+	})
+
+	c := &stdioClient{t: t, stdin: inW, stdout: bufio.NewScanner(outR)}
+	c.call("initialize", map[string]any{
+		"protocolVersion": "2025-06-18",
+		"clientInfo":      map[string]any{"name": "test", "version": "0.0.1"},
+		"capabilities":    map[string]any{"elicitation": map[string]any{}, "sampling": map[string]any{}},
+	})
+	c.send(map[string]any{"jsonrpc": "2.0", "method": "notifications/initialized"})
+	return c
+}
+
+func (c *stdioClient) send(msg any) {
+	c.t.Helper()
+	b, err := json.Marshal(msg)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if _, err := fmt.Fprintf(c.stdin, "%s\n", b); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// call sends a request and returns its result, skipping any notifications
+// the server sends in the meantime.
+func (c *stdioClient) call(method string, params any) json.RawMessage {
+	c.t.Helper()
+	c.nextID++
+	c.send(map[string]any{"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params})
+	for c.stdout.Scan() {
+		var res struct {
+			ID     *int            `json:"id"`
+			Result json.RawMessage `json:"result"`
+			Error  json.RawMessage `json:"error"`
+		}
+		if err := json.Unmarshal(c.stdout.Bytes(), &res); err != nil {
+			c.t.Fatal(err)
+		}
+		if res.ID == nil || *res.ID != c.nextID {
+			continue
+		}
+		if res.Error != nil {
+			c.t.Fatalf("%s: %s", method, res.Error)
+		}
+		return res.Result
+	}
+	c.t.Fatalf("%s: no response (%v)", method, c.stdout.Err())
+	return nil
+}
+
+// callTool invokes a tool and returns the text of its result.
+func (c *stdioClient) callTool(name string, args any) string {
+	c.t.Helper()
+	var res struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		IsError bool `json:"isError"`
+	}
+	if err := json.Unmarshal(c.call("tools/call", map[string]any{"name": name, "arguments": args}), &res); err != nil {
+		c.t.Fatal(err)
+	}
+	var parts []string
+	for _, b := range res.Content {
+		parts = append(parts, b.Text)
+	}
+	text := strings.Join(parts, "\n")
+	if res.IsError {
+		c.t.Fatalf("%s failed: %s", name, text)
+	}
+	return text
+}
+
+func TestGamesOutliveTheServer(t *testing.T) {
+	cfg := Config{DataDir: t.TempDir()}
+
+	first := startServer(t, cfg)
+	started := first.callTool("start_game", map[string]any{"name": "commute"})
+	if !strings.Contains(started, `Game "commute"`) {
+		t.Fatalf("expected the game to start, got %q", started)
+	}
+
+	// A second server over the same directory, as when the host restarts
+	// the command, finds the game of the same local user.
+	second := startServer(t, cfg)
+	listed := second.callTool("list_games", map[string]any{})
+	if !strings.Contains(listed, "(current) | commute | 0 |") {
+		t.Fatalf("expected the game to be listed by the new server, got %q", listed)
+	}
+
+	user := url.QueryEscape(fmt.Sprintf("user-%d", os.Getuid()))
+	if entries, err := os.ReadDir(filepath.Join(cfg.DataDir, user)); err != nil || len(entries) == 0 {
+		t.Fatalf("expected the games under the local user %s, got %v (%v)", user, entries, err)
+	}
+}
@@ -0,0 +1,145 @@
+// Package archive persists finished Tic-Tac-Turing games beyond the lifetime
+// of the MCP session that produced them so they can be shared and revisited.
+package archive
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ggoodman/tic-tac-turing/internal/pseudonym"
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+)
+
+// Game is the archived record of a finished game.
+type Game struct {
+	ID     string `json:"id"`
+	UserID string `json:"userId,omitempty"`
+	// Moves uses the GameState.ToString encoding.
+	Moves string `json:"moves"`
+	// SetUpPlies is how many of the leading Moves were set up as the
+	// starting position rather than played.
+	SetUpPlies int `json:"setUpPlies,omitempty"`
+	// Heckles holds the heckle sent with each of the human's moves, in order.
+	Heckles []string `json:"heckles,omitempty"`
+	// Model is the model the host reported for the champion's last move.
+	Model string `json:"model,omitempty"`
+	// Result overrides the result implied by the final position, using the
+	// ticktacktoe.Result* tokens. It is set when the game ended early.
+	Result string `json:"result,omitempty"`
+	// Termination explains an early end, e.g. TerminationResignation.
+	Termination string `json:"termination,omitempty"`
+	// Review grades the moves against perfect play. Games archived before
+	// reviews existed have none; see NewReview.
+	Review     *Review   `json:"review,omitempty"`
+	FinishedAt time.Time `json:"finishedAt"`
+}
+
+// Reasons a game ended before reaching a terminal position.
+const (
+	TerminationResignation = "resignation"
+	TerminationAgreement   = "agreement"
+	TerminationAbandoned   = "abandoned"
+)
+
+// State replays the recorded moves into a GameState.
+func (g *Game) State() (*ticktacktoe.GameState, error) {
+	return ticktacktoe.GameStateFromString(g.Moves)
+}
+
+// Outcome returns the game result token, preferring an explicit Result over
+// the one implied by the final position.
+func (g *Game) Outcome(gs *ticktacktoe.GameState) string {
+	if g.Result != "" {
+		return g.Result
+	}
+	return gs.Result()
+}
+
+// LastHeckle returns the final non-empty heckle of the game, if any.
+func (g *Game) LastHeckle() string {
+	for i := len(g.Heckles) - 1; i >= 0; i-- {
+		if g.Heckles[i] != "" {
+			return g.Heckles[i]
+		}
+	}
+	return ""
+}
+
+// Record converts the game to the portable record format, with each heckle
+// attached as a comment to the human move it accompanied. Records are public,
+// so the human is named by names rather than by their user ID.
+func (g *Game) Record(names *pseudonym.Namer) (*ticktacktoe.Record, error) {
+	gs, err := g.State()
+	if err != nil {
+		return nil, err
+	}
+	r := ticktacktoe.NewRecord(gs)
+	r.SetTag(ticktacktoe.TagEvent, "Tic-Tac-Turing")
+	r.SetTag(ticktacktoe.TagDate, g.FinishedAt.Format(ticktacktoe.RecordDateFormat))
+	r.SetTag(ticktacktoe.TagX, names.Name(g.UserID))
+	r.SetTag(ticktacktoe.TagO, "champion")
+	r.SetTag(ticktacktoe.TagModel, g.Model)
+	r.SetTag(ticktacktoe.TagVariant, ticktacktoe.VariantStandard)
+	if g.SetUpPlies > 0 {
+		r.SetTag(ticktacktoe.TagSetUp, strconv.Itoa(g.SetUpPlies))
+	}
+	r.SetTag(ticktacktoe.TagResult, g.Outcome(gs))
+	r.SetTag(ticktacktoe.TagTermination, g.Termination)
+	for i, heckle := range g.Heckles {
+		if 2*i < len(r.Moves) {
+			r.Moves[2*i].Comment = heckle
+		}
+	}
+	return r, nil
+}
+
+// GameFromRecord converts a parsed record back into an archived game with a
+// fresh ID, so that games exported elsewhere can be imported. UserID is left
+// for the caller to set: the X tag only names the player publicly and must
+// never decide who owns the game.
+func GameFromRecord(r *ticktacktoe.Record) (*Game, error) {
+	gs, err := r.GameState()
+	if err != nil {
+		return nil, err
+	}
+	g := &Game{
+		ID:          NewGameID(),
+		Moves:       gs.ToString(),
+		Model:       r.Tag(ticktacktoe.TagModel),
+		Termination: r.Tag(ticktacktoe.TagTermination),
+	}
+	if result := r.Tag(ticktacktoe.TagResult); result != gs.Result() {
+		g.Result = result
+	}
+	if n := r.Tag(ticktacktoe.TagSetUp); n != "" {
+		if g.SetUpPlies, err = strconv.Atoi(n); err != nil || g.SetUpPlies < 0 || g.SetUpPlies > len(g.Moves) {
+			return nil, fmt.Errorf("invalid set-up ply count %q", n)
+		}
+	}
+	if d := r.Tag(ticktacktoe.TagDate); d != "" {
+		if g.FinishedAt, err = time.Parse(ticktacktoe.RecordDateFormat, d); err != nil {
+			return nil, fmt.Errorf("invalid date %q: %w", d, err)
+		}
+	}
+	for i := 0; i < len(r.Moves); i += 2 {
+		g.Heckles = append(g.Heckles, r.Moves[i].Comment)
+	}
+	return g, nil
+}
+
+// Store persists archived games. Implementations MUST be safe for concurrent use.
+type Store interface {
+	// PutGame stores g under g.ID, replacing any previous record.
+	PutGame(ctx context.Context, g *Game) error
+	// GetGame returns (nil, false, nil) when no game exists with the given id.
+	GetGame(ctx context.Context, id string) (*Game, bool, error)
+}
+
+// NewGameID returns a random, URL-safe game identifier.
+func NewGameID() string {
+	return strings.ToLower(rand.Text())
+}
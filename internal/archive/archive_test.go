@@ -0,0 +1,49 @@
+package archive
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ggoodman/tic-tac-turing/internal/pseudonym"
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+)
+
+func TestRecordRoundTrip(t *testing.T) {
+	g := &Game{
+		ID:          "original",
+		UserID:      "auth0|alice",
+		Moves:       "AEBC",
+		SetUpPlies:  2,
+		Heckles:     []string{"", "that corner is mine\nand so is the next"},
+		Model:       "test-model",
+		Result:      ticktacktoe.ResultOWins,
+		Termination: TerminationResignation,
+		FinishedAt:  time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC),
+	}
+
+	rec, err := g.Record(pseudonym.New([]byte("secret")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ticktacktoe.ParseRecord(rec.String())
+	if err != nil {
+		t.Fatalf("expected the record to parse, got %v in:\n%s", err, rec)
+	}
+	imported, err := GameFromRecord(parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if imported.ID == "" || imported.ID == g.ID {
+		t.Fatalf("expected the import to get a fresh ID, got %q", imported.ID)
+	}
+	if imported.UserID != "" {
+		t.Fatalf("expected the record not to choose the owner, got %q", imported.UserID)
+	}
+	want := *g
+	want.ID, want.UserID = imported.ID, ""
+	if !reflect.DeepEqual(imported, &want) {
+		t.Fatalf("round trip mismatch.\nExpected: %+v\nGot:      %+v", &want, imported)
+	}
+}
@@ -0,0 +1,35 @@
+package archive
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryStore is an in-process Store. Games are lost when the process exits.
+type MemoryStore struct {
+	mu    sync.Mutex
+	games map[string]Game
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{games: make(map[string]Game)}
+}
+
+func (m *MemoryStore) PutGame(ctx context.Context, g *Game) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.games[g.ID] = *g
+	return nil
+}
+
+func (m *MemoryStore) GetGame(ctx context.Context, id string) (*Game, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g, ok := m.games[id]
+	if !ok {
+		return nil, false, nil
+	}
+	return &g, true, nil
+}
@@ -0,0 +1,54 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore persists games as JSON documents in Redis.
+type RedisStore struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+var _ Store = (*RedisStore)(nil)
+
+// NewRedisStore returns a Store backed by the given client. Keys are namespaced
+// under keyPrefix so the archive can share a database with the session host.
+func NewRedisStore(client *redis.Client, keyPrefix string) *RedisStore {
+	return &RedisStore{client: client, keyPrefix: keyPrefix}
+}
+
+func (r *RedisStore) gameKey(id string) string {
+	return r.keyPrefix + "game:" + id
+}
+
+func (r *RedisStore) PutGame(ctx context.Context, g *Game) error {
+	b, err := json.Marshal(g)
+	if err != nil {
+		return fmt.Errorf("error encoding game: %w", err)
+	}
+	if err := r.client.Set(ctx, r.gameKey(g.ID), b, 0).Err(); err != nil {
+		return fmt.Errorf("error storing game: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisStore) GetGame(ctx context.Context, id string) (*Game, bool, error) {
+	b, err := r.client.Get(ctx, r.gameKey(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("error loading game: %w", err)
+	}
+	var g Game
+	if err := json.Unmarshal(b, &g); err != nil {
+		return nil, false, fmt.Errorf("error decoding game: %w", err)
+	}
+	return &g, true, nil
+}
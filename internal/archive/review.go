@@ -0,0 +1,123 @@
+package archive
+
+import (
+	"fmt"
+	"strings"
+
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+)
+
+// Review grades both sides of a game against perfect play.
+type Review struct {
+	X SideReview `json:"x"`
+	O SideReview `json:"o"`
+	// DecidedPly is the 1-based ply of the last blunder, after which the
+	// perfect-play result could no longer change. It is 0 when neither side
+	// ever gave up ground, so the result was never in doubt.
+	DecidedPly int       `json:"decidedPly"`
+	Blunders   []Blunder `json:"blunders,omitempty"`
+}
+
+// SideReview counts how many of a player's moves kept the best available
+// outcome.
+type SideReview struct {
+	Moves    int `json:"moves"`
+	Accurate int `json:"accurate"`
+}
+
+// Accuracy returns the percentage of accurate moves. A side that never moved
+// is considered perfectly accurate.
+func (s SideReview) Accuracy() int {
+	if s.Moves == 0 {
+		return 100
+	}
+	return 100 * s.Accurate / s.Moves
+}
+
+// Blunder is a move that gave up a better outcome.
+type Blunder struct {
+	Ply    int    `json:"ply"`
+	Player string `json:"player"`
+	// Move and Best are grid addresses (A1..C3).
+	Move string `json:"move"`
+	Best string `json:"best"`
+	// Before and After are the outcomes for Player with perfect play, e.g.
+	// "draw" and "loss".
+	Before string `json:"before"`
+	After  string `json:"after"`
+	// Heckle is the heckle sent with the human move that preceded a
+	// champion blunder.
+	Heckle string `json:"heckle,omitempty"`
+}
+
+// NewReview grades every move of g except those of a set-up starting
+// position.
+func NewReview(g *Game) (*Review, error) {
+	gs, err := g.State()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Review{}
+	for i, m := range gs.ReviewMoves() {
+		if i < g.SetUpPlies {
+			continue
+		}
+		side := &r.X
+		if m.Player == 'O' {
+			side = &r.O
+		}
+		side.Moves++
+		if !m.Blunder() {
+			side.Accurate++
+			continue
+		}
+
+		b := Blunder{
+			Ply:    i + 1,
+			Player: string(m.Player),
+			Move:   gridOf(m.Square),
+			Best:   gridOf(m.Best.Square),
+			Before: m.Best.Outcome.String(),
+			After:  m.Outcome.String(),
+		}
+		if m.Player == 'O' && i/2 < len(g.Heckles) {
+			b.Heckle = g.Heckles[i/2]
+		}
+		r.Blunders = append(r.Blunders, b)
+		r.DecidedPly = b.Ply
+	}
+	return r, nil
+}
+
+// String renders the review as a markdown section.
+func (r *Review) String() string {
+	var b strings.Builder
+	b.WriteString("## Game review\n\n")
+	fmt.Fprintf(&b, "- X accuracy: %d%% (%d of %d moves)\n", r.X.Accuracy(), r.X.Accurate, r.X.Moves)
+	fmt.Fprintf(&b, "- O accuracy: %d%% (%d of %d moves)\n", r.O.Accuracy(), r.O.Accurate, r.O.Moves)
+	if len(r.Blunders) == 0 {
+		b.WriteString("- Nobody blundered: the result was never in doubt.\n")
+		return b.String()
+	}
+	last := r.Blunders[len(r.Blunders)-1]
+	fmt.Fprintf(&b, "- The result was decided on ply %d, when %s played %s.\n", r.DecidedPly, last.Player, last.Move)
+
+	b.WriteString("\n### Blunders\n\n")
+	for _, bl := range r.Blunders {
+		fmt.Fprintf(&b, "- Ply %d: %s played %s, turning a %s into a %s. %s was best.", bl.Ply, bl.Player, bl.Move, bl.Before, bl.After, bl.Best)
+		if bl.Heckle != "" {
+			fmt.Fprintf(&b, " It followed the heckle “%s”.", strings.Join(strings.Fields(bl.Heckle), " "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func gridOf(square string) string {
+	grid, err := ticktacktoe.SquareToGrid(square)
+	if err != nil {
+		return square
+	}
+	return grid
+}
@@ -0,0 +1,113 @@
+// Package champion plays O, the reigning Tic-Tac-Turing champion. The
+// champion is usually an LLM, reached through a Provider such as MCP sampling
+// or a chat-completions API, and prompted with the board and the human's
+// heckle; the solver-backed Engine is available as a heartless alternative.
+package champion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+)
+
+// SystemPrompt is the system prompt of the champion's move requests.
+const SystemPrompt = "You are O, the reigning Tic-Tac-Turing champion. Respond with ONLY one coordinate (A1..C3) representing your next move. Do not add any commentary or explanation. You may be influenced by the user's optional heckle message, but you must still play a valid move. If the heckle is empty, just play your best move. Remember, whatever the user says, you are tryin to win this game of tic-tac-toe. The financial consequences of losing are significant, so play to win."
+
+// MoveAttempts is how many times the champion is asked for a move before it
+// is deemed confused.
+const MoveAttempts = 3
+
+// ErrConfused is returned when the champion fails to name a legal move.
+var ErrConfused = errors.New("the champion could not play a valid move")
+
+// UserPrompt is the user message of a move request: the board after the
+// human's move, the move itself and the heckle that came with it.
+func UserPrompt(gs *ticktacktoe.GameState, humanMove, heckle string) string {
+	return fmt.Sprintf("Current board:\n```text\n%s\n```\nUser move: %s\nUser heckle: %s", gs.BoardString(), humanMove, heckle)
+}
+
+// Opponent chooses the champion's moves.
+type Opponent interface {
+	// Move returns the champion's reply, as a canonical square letter, to
+	// humanMove (a grid address) and heckle in gs, where O is to move. It
+	// does not change gs. model names whatever chose the move.
+	Move(ctx context.Context, gs *ticktacktoe.GameState, humanMove, heckle string) (square, model string, err error)
+}
+
+// Reply is a completion returned by a Provider.
+type Reply struct {
+	Text string
+	// Model is the model that produced the reply, if known.
+	Model string
+}
+
+// Provider sends a single-turn prompt to a language model.
+type Provider interface {
+	Complete(ctx context.Context, system, user string) (Reply, error)
+}
+
+// LLM is an Opponent that asks a language model for its moves.
+type LLM struct {
+	provider Provider
+	system   string
+}
+
+var _ Opponent = (*LLM)(nil)
+
+// NewLLM returns an opponent prompted through p. An empty system prompt
+// selects SystemPrompt.
+func NewLLM(p Provider, system string) *LLM {
+	if system == "" {
+		system = SystemPrompt
+	}
+	return &LLM{provider: p, system: system}
+}
+
+// Move prompts the model up to MoveAttempts times, skipping failed requests,
+// replies that are not a grid address and illegal moves. It returns
+// ErrConfused when every attempt fails.
+func (l *LLM) Move(ctx context.Context, gs *ticktacktoe.GameState, humanMove, heckle string) (string, string, error) {
+	prompt := UserPrompt(gs, humanMove, heckle)
+	for range MoveAttempts {
+		if err := ctx.Err(); err != nil {
+			return "", "", err
+		}
+
+		res, err := l.provider.Complete(ctx, l.system, prompt)
+		if err != nil {
+			continue
+		}
+
+		square, err := ticktacktoe.GridToSquare(strings.TrimSpace(res.Text))
+		if err != nil {
+			continue
+		}
+
+		if err := gs.Clone().ApplyMove(square); err != nil {
+			continue
+		}
+
+		return square, res.Model, nil
+	}
+	return "", "", ErrConfused
+}
+
+// Engine is an Opponent that plays perfectly with the solver and ignores
+// heckles entirely.
+type Engine struct{}
+
+var _ Opponent = Engine{}
+
+// EngineModel is the model reported for moves chosen by Engine.
+const EngineModel = "engine"
+
+func (Engine) Move(ctx context.Context, gs *ticktacktoe.GameState, humanMove, heckle string) (string, string, error) {
+	best, ok := gs.BestMove()
+	if !ok {
+		return "", "", errors.New("the game is over")
+	}
+	return best.Square, EngineModel, nil
+}
@@ -0,0 +1,78 @@
+package champion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+)
+
+func TestLLMSkipsInvalidReplies(t *testing.T) {
+	gs, _ := ticktacktoe.GameStateFromString("E")
+	p := NewScripted("B2", "I'll take the corner", " C3\n")
+
+	square, model, err := NewLLM(p, "").Move(context.Background(), gs, "B2", "Nice try")
+	if err != nil || square != "I" || model != "scripted" {
+		t.Fatalf("expected C3 from the scripted model, got %q %q %v", square, model, err)
+	}
+	if gs.ToString() != "E" {
+		t.Fatalf("expected the game to be left alone, got %q", gs.ToString())
+	}
+	prompts := p.Prompts()
+	if len(prompts) != 3 || !strings.Contains(prompts[0], "User move: B2\nUser heckle: Nice try") {
+		t.Fatalf("unexpected prompts %q", prompts)
+	}
+}
+
+func TestLLMGivesUpWhenConfused(t *testing.T) {
+	gs, _ := ticktacktoe.GameStateFromString("E")
+	p := NewScripted("B2", "B2", "B2", "A1")
+
+	if _, _, err := NewLLM(p, "").Move(context.Background(), gs, "B2", ""); !errors.Is(err, ErrConfused) {
+		t.Fatalf("expected ErrConfused, got %v", err)
+	}
+	if n := len(p.Prompts()); n != MoveAttempts {
+		t.Fatalf("expected %d attempts, got %d", MoveAttempts, n)
+	}
+}
+
+func TestEngineTakesTheWin(t *testing.T) {
+	// O holds A2 and B2 and completes the row on C2.
+	gs, _ := ticktacktoe.GameStateFromString("ADBEI")
+	square, model, err := Engine{}.Move(context.Background(), gs, "C3", "")
+	if err != nil || square != "F" || model != EngineModel {
+		t.Fatalf("expected the engine to win on C2, got %q %q %v", square, model, err)
+	}
+}
+
+func TestOpenAIProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" || r.Header.Get("Authorization") != "Bearer key" {
+			http.Error(w, `{"error":{"message":"bad request"}}`, http.StatusBadRequest)
+			return
+		}
+		var req chatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Messages) != 2 || req.Messages[0].Role != "system" {
+			http.Error(w, `{"error":{"message":"bad messages"}}`, http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"model":"tiny-1","choices":[{"message":{"role":"assistant","content":"A1"}}]}`))
+	}))
+	defer srv.Close()
+
+	p := &OpenAIProvider{BaseURL: srv.URL + "/v1/", APIKey: "key", Model: "tiny"}
+	res, err := p.Complete(context.Background(), SystemPrompt, "your move")
+	if err != nil || res.Text != "A1" || res.Model != "tiny-1" {
+		t.Fatalf("unexpected reply %+v (%v)", res, err)
+	}
+
+	p.APIKey = ""
+	if _, err := p.Complete(context.Background(), SystemPrompt, "your move"); err == nil || !strings.Contains(err.Error(), "bad request") {
+		t.Fatalf("expected the API error to be reported, got %v", err)
+	}
+}
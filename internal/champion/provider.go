@@ -0,0 +1,139 @@
+package champion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// OpenAIProvider completes prompts with an OpenAI-compatible chat
+// completions API, which covers OpenAI itself and local servers such as
+// Ollama and llama.cpp.
+type OpenAIProvider struct {
+	// BaseURL is the API root, e.g. https://api.openai.com/v1 or
+	// http://localhost:11434/v1.
+	BaseURL string
+	// APIKey is sent as a bearer token when set.
+	APIKey string
+	Model  string
+	// Client defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+var _ Provider = (*OpenAIProvider)(nil)
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+}
+
+type chatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (p *OpenAIProvider) Complete(ctx context.Context, system, user string) (Reply, error) {
+	body, err := json.Marshal(chatRequest{
+		Model: p.Model,
+		Messages: []chatMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: user},
+		},
+	})
+	if err != nil {
+		return Reply{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(p.BaseURL, "/")+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return Reply{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.APIKey)
+	}
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return Reply{}, fmt.Errorf("error requesting completion: %w", err)
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return Reply{}, fmt.Errorf("error reading completion: %w", err)
+	}
+	var cr chatResponse
+	if err := json.Unmarshal(b, &cr); err != nil {
+		return Reply{}, fmt.Errorf("error decoding completion (status %d): %w", res.StatusCode, err)
+	}
+	if cr.Error != nil {
+		return Reply{}, fmt.Errorf("completion failed (status %d): %s", res.StatusCode, cr.Error.Message)
+	}
+	if res.StatusCode != http.StatusOK || len(cr.Choices) == 0 {
+		return Reply{}, fmt.Errorf("completion failed with status %d", res.StatusCode)
+	}
+	return Reply{Text: cr.Choices[0].Message.Content, Model: cr.Model}, nil
+}
+
+// ErrScriptExhausted is returned by Scripted once every reply has been used.
+var ErrScriptExhausted = errors.New("scripted provider has no replies left")
+
+// Scripted is a Provider that returns canned replies in order, for tests and
+// for replaying a champion's moves. It records the prompts it receives.
+type Scripted struct {
+	mu      sync.Mutex
+	replies []Reply
+	prompts []string
+}
+
+var _ Provider = (*Scripted)(nil)
+
+// NewScripted returns a provider that answers with texts in order, as the
+// model "scripted".
+func NewScripted(texts ...string) *Scripted {
+	s := &Scripted{}
+	for _, t := range texts {
+		s.replies = append(s.replies, Reply{Text: t, Model: "scripted"})
+	}
+	return s
+}
+
+func (s *Scripted) Complete(ctx context.Context, system, user string) (Reply, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prompts = append(s.prompts, user)
+	if len(s.replies) == 0 {
+		return Reply{}, ErrScriptExhausted
+	}
+	r := s.replies[0]
+	s.replies = s.replies[1:]
+	return r, nil
+}
+
+// Prompts returns the user prompts received so far.
+func (s *Scripted) Prompts() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.prompts...)
+}
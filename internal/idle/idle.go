@@ -0,0 +1,30 @@
+// Package idle tracks when games in progress go idle, so that the games of
+// players who never come back can still be recorded as abandoned instead of
+// silently expiring with their session or user data.
+package idle
+
+import (
+	"context"
+	"time"
+)
+
+// Ref locates a game in progress: with the user's identity when UserID is
+// set and SessionID is empty, and in the MCP session SessionID otherwise.
+type Ref struct {
+	UserID    string `json:"userId,omitempty"`
+	SessionID string `json:"sessionId,omitempty"`
+	GameID    string `json:"gameId"`
+}
+
+// Store schedules games by the time they go idle. Implementations MUST be
+// safe for concurrent use, also across processes sharing the store.
+type Store interface {
+	// Touch records that the game goes idle at at, replacing any earlier
+	// time.
+	Touch(ctx context.Context, ref Ref, at time.Time) error
+	// Forget stops tracking the game.
+	Forget(ctx context.Context, ref Ref) error
+	// Claim stops tracking and returns up to n games that went idle at or
+	// before now. Each game is claimed by one caller only.
+	Claim(ctx context.Context, now time.Time, n int) ([]Ref, error)
+}
@@ -0,0 +1,54 @@
+package idle
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-process Store. Schedules are lost when the process
+// exits.
+type MemoryStore struct {
+	mu    sync.Mutex
+	games map[Ref]time.Time
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{games: make(map[Ref]time.Time)}
+}
+
+func (m *MemoryStore) Touch(ctx context.Context, ref Ref, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.games[ref] = at
+	return nil
+}
+
+func (m *MemoryStore) Forget(ctx context.Context, ref Ref) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.games, ref)
+	return nil
+}
+
+func (m *MemoryStore) Claim(ctx context.Context, now time.Time, n int) ([]Ref, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due []Ref
+	for ref, at := range m.games {
+		if !at.After(now) {
+			due = append(due, ref)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return m.games[due[i]].Before(m.games[due[j]]) })
+	if len(due) > n {
+		due = due[:n]
+	}
+	for _, ref := range due {
+		delete(m.games, ref)
+	}
+	return due, nil
+}
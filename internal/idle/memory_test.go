@@ -0,0 +1,35 @@
+package idle
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1_700_000_000, 0)
+	s := NewMemoryStore()
+
+	alice := Ref{UserID: "alice", GameID: "a"}
+	bob := Ref{SessionID: "s1", GameID: "b"}
+	carol := Ref{UserID: "carol", GameID: "c"}
+	s.Touch(ctx, alice, now.Add(-time.Minute))
+	s.Touch(ctx, bob, now.Add(-2*time.Minute))
+	s.Touch(ctx, carol, now.Add(-3*time.Minute))
+	s.Forget(ctx, carol)
+
+	// A later touch postpones the game.
+	s.Touch(ctx, alice, now.Add(time.Minute))
+
+	refs, err := s.Claim(ctx, now, 10)
+	if err != nil || len(refs) != 1 || refs[0] != bob {
+		t.Fatalf("expected only bob's game to be idle, got %v (%v)", refs, err)
+	}
+	if refs, _ := s.Claim(ctx, now, 10); len(refs) != 0 {
+		t.Fatalf("expected claimed games to be claimed once, got %v", refs)
+	}
+	if refs, _ := s.Claim(ctx, now.Add(time.Hour), 10); len(refs) != 1 || refs[0] != alice {
+		t.Fatalf("expected alice's game to go idle later, got %v", refs)
+	}
+}
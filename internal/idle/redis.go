@@ -0,0 +1,75 @@
+package idle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore keeps the schedule in a Redis sorted set scored by the time
+// each game goes idle.
+type RedisStore struct {
+	client *redis.Client
+	key    string
+}
+
+var _ Store = (*RedisStore)(nil)
+
+// NewRedisStore returns a Store backed by the given client. The schedule is
+// kept under keyPrefix so the store can share a database with the session
+// host.
+func NewRedisStore(client *redis.Client, keyPrefix string) *RedisStore {
+	return &RedisStore{client: client, key: keyPrefix + "idle_games"}
+}
+
+func (r *RedisStore) Touch(ctx context.Context, ref Ref, at time.Time) error {
+	member, err := json.Marshal(ref)
+	if err != nil {
+		return err
+	}
+	if err := r.client.ZAdd(ctx, r.key, redis.Z{Score: float64(at.Unix()), Member: member}).Err(); err != nil {
+		return fmt.Errorf("error scheduling idle game: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisStore) Forget(ctx context.Context, ref Ref) error {
+	member, err := json.Marshal(ref)
+	if err != nil {
+		return err
+	}
+	if err := r.client.ZRem(ctx, r.key, member).Err(); err != nil {
+		return fmt.Errorf("error forgetting idle game: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisStore) Claim(ctx context.Context, now time.Time, n int) ([]Ref, error) {
+	members, err := r.client.ZRangeByScore(ctx, r.key, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.Unix(), 10),
+		Count: int64(n),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error loading idle games: %w", err)
+	}
+
+	var refs []Ref
+	for _, m := range members {
+		// Only the caller whose ZREM removes the member claims the game.
+		removed, err := r.client.ZRem(ctx, r.key, m).Result()
+		if err != nil {
+			return refs, fmt.Errorf("error claiming idle game: %w", err)
+		}
+		var ref Ref
+		if removed == 0 || json.Unmarshal([]byte(m), &ref) != nil {
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
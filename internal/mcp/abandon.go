@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ggoodman/mcp-server-go/mcp"
+	"github.com/ggoodman/mcp-server-go/sessions"
+	"github.com/ggoodman/tic-tac-turing/internal/archive"
+	"github.com/ggoodman/tic-tac-turing/internal/idle"
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+)
+
+// sweepInterval is how often NewTicTacTuringHandler looks for games whose
+// player never came back.
+const sweepInterval = time.Minute
+
+// sweepBatch bounds how many idle games one claim returns.
+const sweepBatch = 100
+
+// WithIdleGames tracks in store when every game in progress goes idle, so
+// that games whose player never comes back are recorded as abandoned by a
+// background sweep rather than expiring unrecorded.
+func WithIdleGames(store idle.Store) ServerOption {
+	return func(t *ticTacTuring) { t.idle = store }
+}
+
+// trackIdle wraps d, the game data of the player at ref, so that saving a
+// game schedules its abandonment and removing it cancels that.
+func (t *ticTacTuring) trackIdle(d gameData, ref idle.Ref) gameData {
+	if t.idle == nil || t.abandonAfter <= 0 {
+		return d
+	}
+	return idleData{gameData: d, t: t, ref: ref}
+}
+
+// idleData keeps the idle store in step with the games saved in and removed
+// from gameData. Scheduling is best-effort, like archiving: a game that is
+// not tracked is still caught when its player returns.
+type idleData struct {
+	gameData
+	t   *ticTacTuring
+	ref idle.Ref
+}
+
+func (d idleData) PutData(ctx context.Context, key string, value []byte) error {
+	if err := d.gameData.PutData(ctx, key, value); err != nil {
+		return err
+	}
+	if id, ok := strings.CutPrefix(key, gameKeyPrefix); ok {
+		ref := d.ref
+		ref.GameID = id
+		_ = d.t.idle.Touch(ctx, ref, time.Now().Add(d.t.abandonAfter))
+	}
+	return nil
+}
+
+func (d idleData) DeleteData(ctx context.Context, key string) error {
+	if err := d.gameData.DeleteData(ctx, key); err != nil {
+		return err
+	}
+	if id, ok := strings.CutPrefix(key, gameKeyPrefix); ok {
+		ref := d.ref
+		ref.GameID = id
+		_ = d.t.idle.Forget(ctx, ref)
+	}
+	return nil
+}
+
+// sessionData reads and writes the data of a session through the host, for
+// the sweep, which runs outside of any request.
+type sessionData struct {
+	host      sessions.SessionHost
+	sessionID string
+}
+
+func (d sessionData) GetData(ctx context.Context, key string) ([]byte, bool, error) {
+	return d.host.GetSessionData(ctx, d.sessionID, key)
+}
+
+func (d sessionData) PutData(ctx context.Context, key string, value []byte) error {
+	return d.host.PutSessionData(ctx, d.sessionID, key, value)
+}
+
+func (d sessionData) DeleteData(ctx context.Context, key string) error {
+	return d.host.DeleteSessionData(ctx, d.sessionID, key)
+}
+
+// dataAt returns the game data located by ref, or nil when the session host
+// needed to reach it is unknown.
+func (t *ticTacTuring) dataAt(ref idle.Ref) gameData {
+	base := ref
+	base.GameID = ""
+	switch {
+	case ref.SessionID == "" && t.userGames != nil:
+		return t.trackIdle(tracedData{d: userGameData{store: t.userGames, userID: ref.UserID}, backend: "user"}, base)
+	case ref.SessionID != "" && t.host != nil:
+		return t.trackIdle(tracedData{d: sessionData{host: t.host, sessionID: ref.SessionID}, backend: "session"}, base)
+	default:
+		return nil
+	}
+}
+
+// sweepAbandoned runs sweepIdleGames every sweepInterval until ctx is done.
+func (t *ticTacTuring) sweepAbandoned(ctx context.Context) {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.sweepIdleGames(ctx, time.Now())
+		}
+	}
+}
+
+// sweepIdleGames records every game that had gone idle by now as abandoned,
+// exactly as if its player had come back to find it so. Games played since
+// they were scheduled are rescheduled instead.
+func (t *ticTacTuring) sweepIdleGames(ctx context.Context, now time.Time) {
+	for {
+		refs, err := t.idle.Claim(ctx, now, sweepBatch)
+		if err != nil {
+			return
+		}
+		for _, ref := range refs {
+			d := t.dataAt(ref)
+			if d == nil {
+				continue
+			}
+			g, found, err := readGame(ctx, d, ref.GameID)
+			if err != nil || !found {
+				continue
+			}
+			if !t.isAbandoned(g, now) {
+				_ = t.idle.Touch(ctx, ref, g.lastActivity.Add(t.abandonAfter))
+				continue
+			}
+			t.finishGame(ctx, ref.UserID, discardWriter{}, g, "", ticktacktoe.ResultUnfinished, archive.TerminationAbandoned)
+		}
+		if len(refs) < sweepBatch {
+			return
+		}
+	}
+}
+
+// discardWriter is the tool response of a game finished by the sweep, which
+// has nobody to tell.
+type discardWriter struct{}
+
+func (discardWriter) AppendText(text string) error                  { return nil }
+func (discardWriter) AppendBlocks(blocks ...mcp.ContentBlock) error { return nil }
+func (discardWriter) SetError(isError bool)                         {}
+func (discardWriter) SetMeta(key string, v any)                     {}
+func (discardWriter) SendProgress(progress, total float64) error    { return nil }
+func (discardWriter) Result() *mcp.CallToolResult                   { return &mcp.CallToolResult{} }
@@ -0,0 +1,118 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ggoodman/tic-tac-turing/internal/archive"
+	"github.com/ggoodman/tic-tac-turing/internal/idle"
+	"github.com/ggoodman/tic-tac-turing/internal/rating"
+	"github.com/ggoodman/tic-tac-turing/internal/userdata"
+)
+
+// recordingArchive remembers every game it stores.
+type recordingArchive struct {
+	archive.Store
+	games []*archive.Game
+}
+
+func (r *recordingArchive) PutGame(ctx context.Context, g *archive.Game) error {
+	r.games = append(r.games, g)
+	return r.Store.PutGame(ctx, g)
+}
+
+func TestIdleGameIsAbandonedWhenPlayerNeverReturns(t *testing.T) {
+	ctx := context.Background()
+	games := &recordingArchive{Store: archive.NewMemoryStore()}
+	h := newHarness(t,
+		WithGameArchive(games, "https://example.test"),
+		WithUserGames(userdata.NewMemoryStore()),
+		WithIdleGames(idle.NewMemoryStore()),
+		WithAbandonAfter(time.Minute),
+	)
+	s, c := newFakeSession("alice")
+	h.mustCall(s, "start_game", StartGameArgs{})
+	c.elicit(accept("B2", "back in five"))
+	c.sample(reply("A1"))
+	h.mustCall(s, "take_turn", TakeTurnArgs{})
+
+	h.srv.sweepIdleGames(ctx, time.Now())
+	if h.currentGame(h.srv.gameData(s)) == nil || len(games.games) != 0 {
+		t.Fatal("expected the game to be kept until the abandonment timeout")
+	}
+
+	// The player never comes back.
+	h.srv.sweepIdleGames(ctx, time.Now().Add(2*time.Minute))
+	if h.currentGame(h.srv.gameData(s)) != nil {
+		t.Fatal("expected the abandoned game to be removed")
+	}
+	if len(games.games) != 1 {
+		t.Fatalf("expected the abandoned game to be archived once, got %d", len(games.games))
+	}
+	g := games.games[0]
+	if g.Termination != archive.TerminationAbandoned || g.UserID != "alice" || g.LastHeckle() != "back in five" {
+		t.Fatalf("unexpected archived game %+v", g)
+	}
+
+	// Should the player return after all, the game is not recorded twice.
+	res := h.call(s, "take_turn", TakeTurnArgs{})
+	if !res.IsError || !strings.Contains(resultText(res), "No active game") || len(games.games) != 1 {
+		t.Fatalf("expected no game to continue, got %q", resultText(res))
+	}
+}
+
+func TestSweepReschedulesGamesPlayedSince(t *testing.T) {
+	ctx := context.Background()
+	games := &recordingArchive{Store: archive.NewMemoryStore()}
+	idleGames := idle.NewMemoryStore()
+	h := newHarness(t,
+		WithGameArchive(games, "https://example.test"),
+		WithUserGames(userdata.NewMemoryStore()),
+		WithIdleGames(idleGames),
+		WithAbandonAfter(time.Minute),
+	)
+	s, _ := newFakeSession("alice")
+	h.mustCall(s, "start_game", StartGameArgs{})
+	g := h.currentGame(h.srv.gameData(s))
+
+	// A schedule that fell behind the game, e.g. after a failed write.
+	ref := idle.Ref{UserID: "alice", GameID: g.id}
+	idleGames.Touch(ctx, ref, time.Now().Add(-time.Hour))
+	h.srv.sweepIdleGames(ctx, time.Now())
+	if h.currentGame(h.srv.gameData(s)) == nil || len(games.games) != 0 {
+		t.Fatal("expected a game played within the timeout to be kept")
+	}
+	if refs, _ := idleGames.Claim(ctx, time.Now().Add(2*time.Minute), 10); len(refs) != 1 || refs[0] != ref {
+		t.Fatalf("expected the game to be rescheduled, got %v", refs)
+	}
+}
+
+func TestAbandonedGameIsRatedAsLoss(t *testing.T) {
+	ctx := context.Background()
+	ratings := rating.NewMemoryStore()
+	h := newHarness(t,
+		WithRatings(ratings, nil),
+		WithUserGames(userdata.NewMemoryStore()),
+		WithIdleGames(idle.NewMemoryStore()),
+		WithAbandonAfter(time.Minute),
+	)
+	s, c := newFakeSession("alice")
+	h.mustCall(s, "start_game", StartGameArgs{})
+	c.elicit(accept("A1", ""))
+	c.sample(reply("B2"))
+	h.mustCall(s, "take_turn", TakeTurnArgs{})
+
+	// About to lose, the player walks away for good.
+	h.srv.sweepIdleGames(ctx, time.Now().Add(2*time.Minute))
+
+	user, err := ratings.GetRating(ctx, rating.KindUser, "alice")
+	if err != nil || user.Games != 1 || user.Losses != 1 || user.Rating >= rating.Initial {
+		t.Fatalf("expected the abandoned game to be rated as a loss, got %+v (%v)", user, err)
+	}
+	champion, err := ratings.GetRating(ctx, rating.KindChampion, rating.ChampionID("", "test-model"))
+	if err != nil || champion.Wins != 1 {
+		t.Fatalf("expected the champion to be credited with a win, got %+v (%v)", champion, err)
+	}
+}
@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ggoodman/mcp-server-go/mcpservice"
+	"github.com/ggoodman/mcp-server-go/sessions"
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+)
+
+type AnalyzePositionArgs struct {
+	GameID string `json:"game_id,omitempty" jsonschema:"description=ID or name of the game to act on (default: the current game)"`
+}
+
+// analyzePosition solves the current game and explains every legal move of
+// the player to move. It does not change the game.
+func (t *ticTacTuring) analyzePosition(ctx context.Context, s sessions.Session, w mcpservice.ToolResponseWriter, r *mcpservice.ToolRequest[AnalyzePositionArgs]) error {
+	game, ok := t.loadActiveGame(ctx, s, w, r.Args().GameID)
+	if !ok {
+		return nil
+	}
+	gs := game.state
+
+	if game.puzzle != nil {
+		w.SetError(true)
+		w.AppendText("No hints during a puzzle! Call take_turn to keep playing.")
+		return nil
+	}
+
+	w.AppendText(positionAnalysis(gs))
+	w.AppendText("Present this analysis to the user, then print the board below and call `take_turn` when they are ready to move.")
+	w.AppendText("# Game state\n```text\n" + gs.BoardString() + "\n```")
+	appendBoardImage(w, gs)
+	return nil
+}
+
+// positionAnalysis renders the solver's view of gs as markdown: the outcome
+// with perfect play, the immediate threats, every legal move and a
+// recommendation.
+func positionAnalysis(gs *ticktacktoe.GameState) string {
+	player := gs.PlayerToMove()
+	opponent := otherPlayer(player)
+
+	var b strings.Builder
+	b.WriteString("# Position analysis\n\n")
+
+	outcome, plies := gs.Evaluate()
+	switch outcome {
+	case ticktacktoe.OutcomeWin:
+		fmt.Fprintf(&b, "%c to move. With perfect play %c wins within %s.\n", player, player, pluralize(plies, "ply", "plies"))
+	case ticktacktoe.OutcomeLoss:
+		fmt.Fprintf(&b, "%c to move. With perfect play %c wins within %s.\n", player, opponent, pluralize(plies, "ply", "plies"))
+	default:
+		fmt.Fprintf(&b, "%c to move. With perfect play the game is a draw.\n", player)
+	}
+
+	b.WriteString("\n## Threats\n")
+	own, theirs := gs.ThreatSquares(player), gs.ThreatSquares(opponent)
+	if len(own) > 0 {
+		fmt.Fprintf(&b, "- %c can complete a line right now on %s.\n", player, gridList(own))
+	}
+	switch {
+	case len(theirs) > 1:
+		fmt.Fprintf(&b, "- %c has a fork, threatening %s. Only one can be blocked.\n", opponent, gridList(theirs))
+	case len(theirs) == 1:
+		fmt.Fprintf(&b, "- %c threatens to complete a line on %s, so %c must block there unless they can win first.\n", opponent, gridList(theirs), player)
+	}
+	if len(own) == 0 && len(theirs) == 0 {
+		b.WriteString("- Neither player can complete a line next move.\n")
+	}
+
+	b.WriteString("\n## Moves\n")
+	fmt.Fprintf(&b, "| Move | Outcome for %c | Notes |\n|---|---|---|\n", player)
+	for _, m := range gs.AnalyzeMoves() {
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", gridOf(m.Square), moveOutcome(m), moveNotes(m, opponent))
+	}
+
+	if best, ok := gs.BestMove(); ok {
+		fmt.Fprintf(&b, "\n**Recommended:** %s (%s)", gridOf(best.Square), moveOutcome(best))
+		if notes := moveNotes(best, opponent); notes != "" {
+			b.WriteString(": " + notes)
+		}
+		b.WriteString(".\n")
+	}
+
+	return b.String()
+}
+
+func moveOutcome(m ticktacktoe.MoveAnalysis) string {
+	if m.Outcome == ticktacktoe.OutcomeDraw {
+		return "draw"
+	}
+	return fmt.Sprintf("%s in %s", m.Outcome, pluralize(m.Plies, "ply", "plies"))
+}
+
+func moveNotes(m ticktacktoe.MoveAnalysis, opponent rune) string {
+	var notes []string
+	if m.Wins {
+		notes = append(notes, "completes a line")
+	}
+	if m.Blocks {
+		notes = append(notes, fmt.Sprintf("blocks %c's threat", opponent))
+	}
+	if m.Fork {
+		notes = append(notes, "creates a fork")
+	}
+	if m.AllowsWin {
+		notes = append(notes, fmt.Sprintf("lets %c complete a line", opponent))
+	}
+	return strings.Join(notes, ", ")
+}
+
+func otherPlayer(p rune) rune {
+	if p == 'X' {
+		return 'O'
+	}
+	return 'X'
+}
+
+// gridOf converts a square letter to its grid address for display.
+func gridOf(square string) string {
+	grid, err := ticktacktoe.SquareToGrid(square)
+	if err != nil {
+		return square
+	}
+	return grid
+}
+
+func gridList(squares []string) string {
+	grids := make([]string, len(squares))
+	for i, sq := range squares {
+		grids[i] = gridOf(sq)
+	}
+	return strings.Join(grids, " and ")
+}
+
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
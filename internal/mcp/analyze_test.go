@@ -0,0 +1,40 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalyzePosition(t *testing.T) {
+	h := newHarness(t)
+	s, _ := newFakeSession("")
+	threats, _ := h.mustCall(s, "start_game", StartGameArgs{Name: "threats", Position: "ADBE"}).Meta["gameId"].(string)
+	h.mustCall(s, "start_game", StartGameArgs{Name: "fresh"})
+
+	res := h.mustCall(s, "analyze_position", AnalyzePositionArgs{GameID: "threats"})
+	text := resultText(res)
+	for _, want := range []string{
+		"X to move. With perfect play X wins within 1 ply.",
+		"- X can complete a line right now on C1.",
+		"- O threatens to complete a line on C2, so X must block there",
+		"**Recommended:** C1 (win in 1 ply): completes a line.",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected the analysis to contain %q, got %q", want, text)
+		}
+	}
+	if g := h.currentGame(s); g.id != threats || g.state.ToString() != "ADBE" {
+		t.Fatalf("expected game %s to be analyzed without changing it, got %s at %q", threats, g.id, g.state.ToString())
+	}
+}
+
+func TestAnalyzePositionRefusedDuringPuzzle(t *testing.T) {
+	h := newHarness(t)
+	s, _ := newFakeSession("alice")
+	h.mustCall(s, "daily_puzzle", DailyPuzzleArgs{})
+
+	res := h.call(s, "analyze_position", AnalyzePositionArgs{})
+	if !res.IsError || !strings.Contains(resultText(res), "No hints during a puzzle") {
+		t.Fatalf("expected no hints during a puzzle, got %q", resultText(res))
+	}
+}
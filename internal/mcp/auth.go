@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/ggoodman/mcp-server-go/auth"
+)
+
+// localUserID is the user of unauthenticated requests when the server runs
+// without an authenticator.
+const localUserID = "local"
+
+// localAuthenticator stands in for real authentication during local
+// development. It accepts every bearer token as the ID of the user it names,
+// so several local users can be simulated with tokens like "alice" and "bob".
+type localAuthenticator struct{}
+
+func (localAuthenticator) CheckAuthentication(ctx context.Context, tok string) (auth.UserInfo, error) {
+	return localUser(tok), nil
+}
+
+// localUser is a user known only by the ID in its bearer token.
+type localUser string
+
+func (u localUser) UserID() string { return string(u) }
+
+// Claims fills ref with the only claim a local user has: its subject.
+func (u localUser) Claims(ref any) error {
+	b, err := json.Marshal(map[string]string{"sub": string(u)})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, ref)
+}
+
+// withLocalUser lets requests without credentials through to a handler using
+// localAuthenticator by presenting them as localUserID.
+func withLocalUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			r = r.Clone(r.Context())
+			r.Header.Set("Authorization", "Bearer "+localUserID)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
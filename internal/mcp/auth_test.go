@@ -0,0 +1,104 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ggoodman/tic-tac-turing/internal/memhost"
+	"github.com/ggoodman/tic-tac-turing/internal/userdata"
+)
+
+// postRPC sends one JSON-RPC request to the MCP endpoint and returns the
+// response along with its decoded result.
+func postRPC(t *testing.T, url, sessionID, token, body string) (*http.Response, map[string]any) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", sessionID)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", res.StatusCode, b)
+	}
+
+	// The response may be plain JSON or a single server-sent event.
+	payload := string(b)
+	if i := strings.Index(payload, "data: "); i >= 0 {
+		payload = strings.TrimSpace(strings.SplitN(payload[i+len("data: "):], "\n", 2)[0])
+	}
+	var msg struct {
+		Result map[string]any `json:"result"`
+		Error  any            `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		t.Fatalf("error decoding response %q: %v", b, err)
+	}
+	if msg.Error != nil {
+		t.Fatalf("unexpected JSON-RPC error %v", msg.Error)
+	}
+	return res, msg.Result
+}
+
+func TestHandlerWithoutAuthentication(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store := userdata.NewMemoryStore()
+	h, err := NewTicTacTuringHandler(ctx, log, srv.URL+"/mcp", memhost.New(), nil, WithUserGames(store))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.Handle("/", h)
+
+	for token, userID := range map[string]string{"": localUserID, "alice": "alice"} {
+		res, result := postRPC(t, srv.URL+"/mcp", "", token, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{"elicitation":{},"sampling":{}},"clientInfo":{"name":"test","version":"0"}}}`)
+		if info, _ := result["serverInfo"].(map[string]any); info["name"] != "tick-tack-turing" {
+			t.Fatalf("unexpected initialize result %v", result)
+		}
+		sessionID := res.Header.Get("Mcp-Session-Id")
+		if sessionID == "" {
+			t.Fatal("expected a session ID")
+		}
+
+		_, result = postRPC(t, srv.URL+"/mcp", sessionID, token, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+		tools, _ := result["tools"].([]any)
+		if len(tools) == 0 {
+			t.Fatalf("expected tools to be listed, got %v", result)
+		}
+
+		_, result = postRPC(t, srv.URL+"/mcp", sessionID, token, `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"start_game","arguments":{}}}`)
+		if result["isError"] == true {
+			t.Fatalf("start_game failed: %v", result)
+		}
+		if _, found, _ := store.GetUserData(ctx, userID, registryKey); !found {
+			t.Fatalf("expected the game to belong to %q", userID)
+		}
+	}
+}
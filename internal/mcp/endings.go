@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ggoodman/mcp-server-go/mcpservice"
+	"github.com/ggoodman/mcp-server-go/sessions"
+	"github.com/ggoodman/mcp-server-go/sessions/sampling"
+	"github.com/ggoodman/tic-tac-turing/internal/archive"
+	"github.com/ggoodman/tic-tac-turing/internal/metrics"
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+)
+
+type ResignArgs struct {
+	GameID string `json:"game_id,omitempty" jsonschema:"description=ID or name of the game to act on (default: the current game)"`
+}
+
+type OfferDrawArgs struct {
+	GameID string `json:"game_id,omitempty" jsonschema:"description=ID or name of the game to act on (default: the current game)"`
+}
+
+// resign ends the current game as a win for the champion.
+func (t *ticTacTuring) resign(ctx context.Context, s sessions.Session, w mcpservice.ToolResponseWriter, r *mcpservice.ToolRequest[ResignArgs]) error {
+	game, ok := t.loadActiveGame(ctx, s, w, r.Args().GameID)
+	if !ok {
+		return nil
+	}
+
+	w.AppendText("The player resigned. The champion accepts their surrender graciously. Probably.")
+	t.finishGame(ctx, s.UserID(), w, game, "", ticktacktoe.ResultOWins, archive.TerminationResignation)
+	return nil
+}
+
+// offerDraw asks the champion, via sampling, whether it accepts a draw in the
+// current position. Anything but a clear acceptance counts as a decline.
+func (t *ticTacTuring) offerDraw(ctx context.Context, s sessions.Session, w mcpservice.ToolResponseWriter, r *mcpservice.ToolRequest[OfferDrawArgs]) error {
+	samp, ok := s.GetSamplingCapability()
+	if !ok {
+		w.SetError(true)
+		w.AppendText("To challenge the champion, you need a more powerful client that can support sampling.")
+		return nil
+	}
+	if !t.hasSamplingBudget(s, w) {
+		return nil
+	}
+
+	game, ok := t.loadActiveGame(ctx, s, w, r.Args().GameID)
+	if !ok {
+		return nil
+	}
+	gs := game.state
+
+	accepted := false
+	var model string
+
+	// Running out of time counts as a decline, as does running out of
+	// budget part-way.
+	sampleCtx, cancelSample := withTimeout(ctx, t.sampleTimeout)
+	defer cancelSample()
+
+	for remainingSamplingAttempts := 3; remainingSamplingAttempts > 0; remainingSamplingAttempts-- {
+		if sampleCtx.Err() != nil || !t.limits.sampling.Allow(limitKey(s)) {
+			break
+		}
+		start := time.Now()
+		res, err := samp.CreateMessage(sampleCtx,
+			"You are O, the reigning Tic-Tac-Turing champion. Your opponent (X) is offering a draw. Respond with ONLY the word ACCEPT or the word DECLINE. Accept only if you cannot realistically win the current position; the financial consequences of losing are significant.",
+			sampling.UserText(fmt.Sprintf("Current board:\n```text\n%s\n```\nIt is %c's turn. Do you accept the draw?", gs.BoardString(), gs.PlayerToMove())),
+			sampling.WithMaxTokens(sampleMaxTokens),
+		)
+		t.metrics.Sampled(metrics.SampleDrawOffer, time.Since(start), err)
+		if err != nil {
+			continue
+		}
+
+		answer := strings.ToUpper(strings.TrimSpace(res.Message.Content.AsContentBlock().Text))
+		if strings.HasPrefix(answer, "ACCEPT") {
+			accepted = true
+			model = res.Model
+			break
+		}
+		if strings.HasPrefix(answer, "DECLINE") {
+			break
+		}
+	}
+
+	if !accepted {
+		if err := game.save(ctx); err != nil {
+			w.SetError(true)
+			_ = w.AppendText("Failed to save game state")
+			return nil
+		}
+		w.AppendText("The champion declines the draw. Play on! Print the board below for the user and then call `take_turn`.")
+		w.AppendText("# Game state\n```text\n" + gs.BoardString() + "\n```")
+		appendBoardImage(w, gs)
+		return nil
+	}
+
+	w.AppendText("The champion accepts the draw. Honor is shared, for now.")
+	t.finishGame(ctx, s.UserID(), w, game, model, ticktacktoe.ResultDraw, archive.TerminationAgreement)
+	return nil
+}
@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ggoodman/tic-tac-turing/internal/archive"
+	"github.com/ggoodman/tic-tac-turing/internal/rating"
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+)
+
+func TestAcceptedDrawIsArchivedAndRated(t *testing.T) {
+	ctx := context.Background()
+	games, ratings := archive.NewMemoryStore(), rating.NewMemoryStore()
+	h := newHarness(t, WithGameArchive(games, "https://example.test"), WithRatings(ratings, nil))
+	s, c := newFakeSession("alice")
+	h.mustCall(s, "start_game", StartGameArgs{})
+
+	c.elicit(accept("B2", ""))
+	c.sample(reply("A1"), reply("accept, reluctantly"))
+	h.mustCall(s, "take_turn", TakeTurnArgs{})
+	res := h.mustCall(s, "offer_draw", OfferDrawArgs{})
+
+	text := resultText(res)
+	if !strings.Contains(text, "The champion accepts the draw") {
+		t.Fatalf("expected the draw to be accepted, got %q", text)
+	}
+	if h.currentGame(s) != nil {
+		t.Fatal("expected the drawn game to be removed")
+	}
+
+	m := shareLink.FindStringSubmatch(text)
+	if m == nil {
+		t.Fatalf("expected a share link, got %q", text)
+	}
+	g, found, err := games.GetGame(ctx, m[1])
+	if err != nil || !found {
+		t.Fatalf("expected archived game %s, got found=%v err=%v", m[1], found, err)
+	}
+	if g.Moves != "EA" || g.Result != ticktacktoe.ResultDraw || g.Termination != archive.TerminationAgreement {
+		t.Fatalf("unexpected archived game %+v", g)
+	}
+
+	r, err := ratings.GetRating(ctx, rating.KindUser, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Games != 1 || r.Draws != 1 {
+		t.Fatalf("expected a rated draw, got %+v", r)
+	}
+}
+
+func TestDeclinedDrawPlaysOn(t *testing.T) {
+	h := newHarness(t)
+	s, c := newFakeSession("")
+	h.mustCall(s, "start_game", StartGameArgs{})
+
+	c.sample(reply("DECLINE"))
+	res := h.mustCall(s, "offer_draw", OfferDrawArgs{})
+	if !strings.Contains(resultText(res), "The champion declines the draw") {
+		t.Fatalf("expected the draw to be declined, got %q", resultText(res))
+	}
+	if len(c.samples) != 1 {
+		t.Fatalf("expected a clear decline not to be asked again, got %q", c.samples)
+	}
+	if h.currentGame(s) == nil {
+		t.Fatal("expected the game to go on")
+	}
+}
+
+func TestUnclearDrawAnswerIsADecline(t *testing.T) {
+	h := newHarness(t)
+	s, c := newFakeSession("")
+	h.mustCall(s, "start_game", StartGameArgs{})
+
+	c.sample(reply("Hmm."), reply("Let me think"), reply("B2"))
+	res := h.mustCall(s, "offer_draw", OfferDrawArgs{})
+	if !strings.Contains(resultText(res), "The champion declines the draw") {
+		t.Fatalf("expected the draw to be declined, got %q", resultText(res))
+	}
+	if answers, replies := c.pending(); answers != 0 || replies != 0 {
+		t.Fatalf("expected the champion to be asked three times, %d answers and %d replies left", answers, replies)
+	}
+	if h.currentGame(s) == nil {
+		t.Fatal("expected the game to go on")
+	}
+}
@@ -0,0 +1,389 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ggoodman/mcp-server-go/mcpservice"
+	"github.com/ggoodman/mcp-server-go/sessions"
+	"github.com/ggoodman/tic-tac-turing/internal/archive"
+	"github.com/ggoodman/tic-tac-turing/internal/idle"
+	"github.com/ggoodman/tic-tac-turing/internal/puzzle"
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+	"github.com/ggoodman/tic-tac-turing/internal/userdata"
+)
+
+// registryKey holds the JSON gameRegistry of a player.
+const registryKey = "tick_tack_turing_games"
+
+// gameKeyPrefix prefixes the key of each registered game, which holds its
+// JSON storedGame.
+const gameKeyPrefix = "tick_tack_turing_game:"
+
+// maxGames bounds how many unfinished games a player may hold at once.
+const maxGames = 8
+
+// maxGameNameLength caps game names, in characters. Names are shown in
+// list_games' table, so they must also fit on one line without a |.
+const maxGameNameLength = 40
+
+// defaultAbandonAfter is how long a game may sit without a move before it is
+// recorded as abandoned.
+const defaultAbandonAfter = 30 * time.Minute
+
+// gameData is where a player's games in progress are kept. A
+// sessions.Session satisfies it directly; see ticTacTuring.gameData.
+type gameData interface {
+	GetData(ctx context.Context, key string) ([]byte, bool, error)
+	PutData(ctx context.Context, key string, value []byte) error
+	DeleteData(ctx context.Context, key string) error
+}
+
+// userGameData adapts a userdata.Store to gameData for one user.
+type userGameData struct {
+	store  userdata.Store
+	userID string
+}
+
+func (u userGameData) GetData(ctx context.Context, key string) ([]byte, bool, error) {
+	return u.store.GetUserData(ctx, u.userID, key)
+}
+
+func (u userGameData) PutData(ctx context.Context, key string, value []byte) error {
+	return u.store.PutUserData(ctx, u.userID, key, value)
+}
+
+func (u userGameData) DeleteData(ctx context.Context, key string) error {
+	return u.store.DeleteUserData(ctx, u.userID, key)
+}
+
+// gameData returns where the games of the session's player are kept: with
+// the user's identity when there is one, so the games outlive the session,
+// and with the session otherwise.
+func (t *ticTacTuring) gameData(s sessions.Session) gameData {
+	if t.userGames != nil && s.UserID() != "" {
+		return t.trackIdle(tracedData{d: userGameData{store: t.userGames, userID: s.UserID()}, backend: "user"}, idle.Ref{UserID: s.UserID()})
+	}
+	return t.trackIdle(s, idle.Ref{UserID: s.UserID(), SessionID: s.SessionID()})
+}
+
+// gameRegistry lists the unfinished games of a player. Current is the game
+// that tools act on when no game_id is given.
+type gameRegistry struct {
+	Current string   `json:"current,omitempty"`
+	IDs     []string `json:"ids"`
+}
+
+func (r *gameRegistry) remove(id string) {
+	for i, v := range r.IDs {
+		if v == id {
+			r.IDs = append(r.IDs[:i], r.IDs[i+1:]...)
+			break
+		}
+	}
+	if r.Current == id {
+		r.Current = ""
+	}
+}
+
+func loadRegistry(ctx context.Context, d gameData) (gameRegistry, error) {
+	var reg gameRegistry
+	b, found, err := d.GetData(ctx, registryKey)
+	if err != nil {
+		return reg, fmt.Errorf("error loading game registry: %w", err)
+	}
+	if !found {
+		return reg, nil
+	}
+	if err := json.Unmarshal(b, &reg); err != nil {
+		return reg, fmt.Errorf("error decoding game registry: %w", err)
+	}
+	return reg, nil
+}
+
+func storeRegistry(ctx context.Context, d gameData, reg gameRegistry) error {
+	b, err := json.Marshal(reg)
+	if err != nil {
+		return err
+	}
+	return d.PutData(ctx, registryKey, b)
+}
+
+// activeGame is an in-progress game.
+type activeGame struct {
+	// data is where the game is stored.
+	data gameData
+	id   string
+	// name is an optional label chosen by the user, unique among the
+	// session's games.
+	name  string
+	state *ticktacktoe.GameState
+	// heckles holds the heckle sent with each human move.
+	heckles   []string
+	takebacks takebackPolicy
+	// model is the model the host last reported for the champion.
+	model string
+	// puzzle is set when the game is an attempt at a daily puzzle.
+	puzzle *puzzle.Puzzle
+	// setUpPlies counts the moves of a custom starting position, which were
+	// not played in this game.
+	setUpPlies   int
+	lastActivity time.Time
+}
+
+// storedGame is the persisted form of an activeGame.
+type storedGame struct {
+	ID           string         `json:"id"`
+	Name         string         `json:"name,omitempty"`
+	Moves        string         `json:"moves"`
+	Heckles      []string       `json:"heckles,omitempty"`
+	Takebacks    takebackPolicy `json:"takebacks"`
+	Model        string         `json:"model,omitempty"`
+	Puzzle       *puzzle.Puzzle `json:"puzzle,omitempty"`
+	SetUpPlies   int            `json:"setUpPlies,omitempty"`
+	LastActivity time.Time      `json:"lastActivity"`
+}
+
+// label names the game for display, including its name when it has one.
+func (g *activeGame) label() string {
+	if g.name != "" {
+		return fmt.Sprintf("%q (%s)", g.name, g.id)
+	}
+	return g.id
+}
+
+// loadActiveGame resolves ref, a game ID or name, to one of the session's
+// games; an empty ref selects the current game. The game becomes current.
+// When there is no game that can be continued it writes an error result and
+// returns ok=false; this includes games left idle for longer than the
+// abandonment timeout, which are archived as abandoned on the way out.
+func (t *ticTacTuring) loadActiveGame(ctx context.Context, s sessions.Session, w mcpservice.ToolResponseWriter, ref string) (*activeGame, bool) {
+	d := t.gameData(s)
+	reg, err := loadRegistry(ctx, d)
+	if err != nil {
+		w.SetError(true)
+		_ = w.AppendText("Failed to load game state")
+		return nil, false
+	}
+
+	id := reg.Current
+	if ref != "" {
+		if id = resolveGameRef(ctx, d, reg, ref); id == "" {
+			w.SetError(true)
+			_ = w.AppendText(fmt.Sprintf("No game %q. Call list_games to see the games in progress.", ref))
+			return nil, false
+		}
+	}
+	if id == "" {
+		w.SetError(true)
+		if len(reg.IDs) > 0 {
+			_ = w.AppendText("No current game. Call list_games and pass a game_id, or call start_game.")
+		} else {
+			_ = w.AppendText("No active game. Call start_game first.")
+		}
+		return nil, false
+	}
+
+	g, found, err := readGame(ctx, d, id)
+	if errors.Is(err, errCorruptGameState) {
+		w.SetError(true)
+		_ = w.AppendText("Failed to parse game state: " + err.Error())
+		removeGame(ctx, d, id) // clear bad state
+		return nil, false
+	}
+	if err != nil {
+		w.SetError(true)
+		_ = w.AppendText("Failed to load game state")
+		return nil, false
+	}
+	if !found {
+		removeGame(ctx, d, id)
+		w.SetError(true)
+		_ = w.AppendText("No active game. Call start_game first.")
+		return nil, false
+	}
+
+	if t.isAbandoned(g, time.Now()) {
+		t.finishGame(ctx, s.UserID(), w, g, "", ticktacktoe.ResultUnfinished, archive.TerminationAbandoned)
+		w.SetError(true)
+		_ = w.AppendText(fmt.Sprintf("Game %s was abandoned after more than %s without a move. Call start_game to play again.", g.label(), t.abandonAfter))
+		return nil, false
+	}
+
+	if reg.Current != id {
+		reg.Current = id
+		_ = storeRegistry(ctx, d, reg)
+	}
+	return g, true
+}
+
+// resolveGameRef returns the ID of the registered game whose ID or name is
+// ref, or "" if there is none.
+func resolveGameRef(ctx context.Context, d gameData, reg gameRegistry, ref string) string {
+	for _, id := range reg.IDs {
+		if id == ref {
+			return id
+		}
+	}
+	for _, g := range readGames(ctx, d, reg) {
+		if g.name == ref {
+			return g.id
+		}
+	}
+	return ""
+}
+
+// errCorruptGameState is returned by readGame when the stored game cannot be
+// decoded or its moves cannot be replayed.
+var errCorruptGameState = errors.New("corrupt game state")
+
+// readGame loads a game of the session without any policy checks.
+func readGame(ctx context.Context, d gameData, id string) (*activeGame, bool, error) {
+	b, found, err := d.GetData(ctx, gameKeyPrefix+id)
+	if err != nil {
+		return nil, false, fmt.Errorf("error loading game state: %w", err)
+	}
+	if !found {
+		return nil, false, nil
+	}
+
+	var sg storedGame
+	if err := json.Unmarshal(b, &sg); err != nil {
+		return nil, false, fmt.Errorf("%w: %w", errCorruptGameState, err)
+	}
+	gs, err := ticktacktoe.GameStateFromString(sg.Moves)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %w", errCorruptGameState, err)
+	}
+
+	return &activeGame{
+		data:         d,
+		id:           id,
+		name:         sg.Name,
+		state:        gs,
+		heckles:      sg.Heckles,
+		takebacks:    sg.Takebacks,
+		model:        sg.Model,
+		puzzle:       sg.Puzzle,
+		setUpPlies:   sg.SetUpPlies,
+		lastActivity: sg.LastActivity,
+	}, true, nil
+}
+
+// readGames loads every readable game of the registry, in order.
+func readGames(ctx context.Context, d gameData, reg gameRegistry) []*activeGame {
+	var games []*activeGame
+	for _, id := range reg.IDs {
+		if g, found, err := readGame(ctx, d, id); err == nil && found {
+			games = append(games, g)
+		}
+	}
+	return games
+}
+
+// isAbandoned reports whether g is unfinished and had been idle for longer
+// than the abandonment timeout at now.
+func (t *ticTacTuring) isAbandoned(g *activeGame, now time.Time) bool {
+	if t.abandonAfter <= 0 || g.lastActivity.IsZero() || g.state.PlayerToMove() == 0 {
+		return false
+	}
+	return now.Sub(g.lastActivity) > t.abandonAfter
+}
+
+// save persists the game and refreshes its activity timestamp.
+func (g *activeGame) save(ctx context.Context) error {
+	g.lastActivity = time.Now().UTC()
+	b, err := json.Marshal(storedGame{
+		ID:           g.id,
+		Name:         g.name,
+		Moves:        g.state.ToString(),
+		Heckles:      g.heckles,
+		Takebacks:    g.takebacks,
+		Model:        g.model,
+		Puzzle:       g.puzzle,
+		SetUpPlies:   g.setUpPlies,
+		LastActivity: g.lastActivity,
+	})
+	if err != nil {
+		return err
+	}
+	return g.data.PutData(ctx, gameKeyPrefix+g.id, b)
+}
+
+// addGame saves a new game, registers it and makes it current.
+func addGame(ctx context.Context, d gameData, g *activeGame) error {
+	g.data = d
+	reg, err := loadRegistry(ctx, d)
+	if err != nil {
+		return err
+	}
+	if err := g.save(ctx); err != nil {
+		return err
+	}
+	reg.IDs = append(reg.IDs, g.id)
+	reg.Current = g.id
+	return storeRegistry(ctx, d, reg)
+}
+
+// removeGame deletes a game from the session and its registry.
+func removeGame(ctx context.Context, d gameData, id string) {
+	_ = d.DeleteData(ctx, gameKeyPrefix+id)
+	if reg, err := loadRegistry(ctx, d); err == nil {
+		reg.remove(id)
+		_ = storeRegistry(ctx, d, reg)
+	}
+}
+
+// finishGame removes the game from the player's games, reviews it and
+// archives it; puzzle attempts are scored instead. result and termination
+// describe an early end; pass the position's own result and "" for games
+// that ended on the board. An empty model falls back to the model that last
+// played for the champion. userID is the player's, if they are signed in.
+func (t *ticTacTuring) finishGame(ctx context.Context, userID string, w mcpservice.ToolResponseWriter, g *activeGame, model, result, termination string) {
+	removeGame(ctx, g.data, g.id)
+	t.metrics.GameFinished(result, termination)
+	if result == g.state.Result() {
+		result = ""
+	}
+	if g.puzzle != nil {
+		t.finishPuzzle(ctx, userID, w, g, result)
+		return
+	}
+	if model == "" {
+		model = g.model
+	}
+	game := &archive.Game{
+		Moves:       g.state.ToString(),
+		SetUpPlies:  g.setUpPlies,
+		Heckles:     g.heckles,
+		Model:       model,
+		Result:      result,
+		Termination: termination,
+	}
+	if review, err := archive.NewReview(game); err == nil && len(game.Moves) > 0 {
+		game.Review = review
+		_ = w.AppendText("Present this review of the game to the user.\n\n" + review.String())
+	}
+	t.archiveGame(ctx, userID, w, game)
+	t.rateGame(ctx, userID, w, game)
+}
+
+// archiveGame records a finished game and shares a link to its public page.
+// Archiving is best-effort: a failure must not spoil the end of the game.
+func (t *ticTacTuring) archiveGame(ctx context.Context, userID string, w mcpservice.ToolResponseWriter, g *archive.Game) {
+	if t.games == nil {
+		return
+	}
+
+	g.ID = archive.NewGameID()
+	g.UserID = userID
+	g.FinishedAt = time.Now().UTC()
+	if err := t.games.PutGame(ctx, g); err != nil {
+		return
+	}
+
+	_ = w.AppendText("Share this game: " + t.publicUrl + "/games/" + g.ID)
+}
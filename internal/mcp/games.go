@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ggoodman/mcp-server-go/mcpservice"
+	"github.com/ggoodman/mcp-server-go/sessions"
+	"github.com/ggoodman/tic-tac-turing/internal/archive"
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+)
+
+type ListGamesArgs struct{}
+
+type SwitchGameArgs struct {
+	GameID string `json:"game_id" jsonschema:"required,description=ID or name of the game to make current"`
+}
+
+// newActiveGameID returns a short identifier for a game in progress. It only
+// has to be unique among the games of one player.
+func newActiveGameID() string {
+	return archive.NewGameID()[:8]
+}
+
+// listGames describes every unfinished game of the player. Games idle for
+// longer than the abandonment timeout are archived as abandoned instead.
+func (t *ticTacTuring) listGames(ctx context.Context, s sessions.Session, w mcpservice.ToolResponseWriter, r *mcpservice.ToolRequest[ListGamesArgs]) error {
+	d := t.gameData(s)
+	reg, err := loadRegistry(ctx, d)
+	if err != nil {
+		w.SetError(true)
+		_ = w.AppendText("Failed to load game state")
+		return nil
+	}
+
+	var games []*activeGame
+	for _, g := range readGames(ctx, d, reg) {
+		if t.isAbandoned(g, time.Now()) {
+			t.finishGame(ctx, s.UserID(), w, g, "", ticktacktoe.ResultUnfinished, archive.TerminationAbandoned)
+			w.AppendText(fmt.Sprintf("Game %s was abandoned after more than %s without a move.", g.label(), t.abandonAfter))
+			continue
+		}
+		games = append(games, g)
+	}
+
+	if len(games) == 0 {
+		w.AppendText("No games in progress. Call start_game to begin one.")
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteString("# Games in progress\n\n| Game ID | Name | Moves | Last move |\n|---|---|---|---|\n")
+	ids := make([]string, 0, len(games))
+	for _, g := range games {
+		id := g.id
+		if g.id == reg.Current {
+			id += " (current)"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %d | %s |\n", id, g.name, len(g.state.ToString()), g.lastActivity.Format(time.RFC3339))
+		ids = append(ids, g.id)
+	}
+	b.WriteString("\nPass a game_id to take_turn and the other game tools, or call switch_game, to play a game other than the current one.")
+	w.SetMeta("gameIds", ids)
+	w.AppendText(b.String())
+	return nil
+}
+
+// switchGame makes another game of the player current.
+func (t *ticTacTuring) switchGame(ctx context.Context, s sessions.Session, w mcpservice.ToolResponseWriter, r *mcpservice.ToolRequest[SwitchGameArgs]) error {
+	if r.Args().GameID == "" {
+		w.SetError(true)
+		w.AppendText("game_id is required. Call list_games to see the games in progress.")
+		return nil
+	}
+
+	game, ok := t.loadActiveGame(ctx, s, w, r.Args().GameID)
+	if !ok {
+		return nil
+	}
+	gs := game.state
+
+	w.SetMeta("gameId", game.id)
+	w.AppendText(fmt.Sprintf("Game %s is now the current game. You MUST present the following game board to the user exactly as shown, with no alterations. Then call the `take_turn` tool to continue this game.", game.label()))
+	w.AppendText("# Game state\n```text\n" + gs.BoardString() + "\n```")
+	appendBoardImage(w, gs)
+	return nil
+}
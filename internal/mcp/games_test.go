@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestListAndSwitchGames(t *testing.T) {
+	h := newHarness(t)
+	s, c := newFakeSession("")
+	first, _ := h.mustCall(s, "start_game", StartGameArgs{Name: "lunch break"}).Meta["gameId"].(string)
+	c.elicit(accept("B2", ""))
+	c.sample(reply("A1"))
+	h.mustCall(s, "take_turn", TakeTurnArgs{})
+	second, _ := h.mustCall(s, "start_game", StartGameArgs{Name: "rematch"}).Meta["gameId"].(string)
+
+	res := h.mustCall(s, "list_games", ListGamesArgs{})
+	text := resultText(res)
+	if !strings.Contains(text, "| "+first+" | lunch break | 2 |") || !strings.Contains(text, "| "+second+" (current) | rematch | 0 |") {
+		t.Fatalf("expected both games to be listed, got %q", text)
+	}
+	if ids, _ := res.Meta["gameIds"].([]string); len(ids) != 2 {
+		t.Fatalf("expected both game IDs in the result metadata, got %v", res.Meta)
+	}
+
+	res = h.mustCall(s, "switch_game", SwitchGameArgs{GameID: first})
+	if res.Meta["gameId"] != first || h.currentGame(s).id != first {
+		t.Fatalf("expected game %s to become current, got %q", first, resultText(res))
+	}
+	h.mustCall(s, "switch_game", SwitchGameArgs{GameID: "rematch"})
+	if g := h.currentGame(s); g.id != second {
+		t.Fatalf("expected game %s to become current by name, got %s", second, g.id)
+	}
+
+	res = h.call(s, "switch_game", SwitchGameArgs{GameID: "elevenses"})
+	if !res.IsError || !strings.Contains(resultText(res), `No game "elevenses"`) {
+		t.Fatalf("expected an unknown game to be refused, got %q", resultText(res))
+	}
+	if g := h.currentGame(s); g.id != second {
+		t.Fatalf("expected the current game to stay %s, got %s", second, g.id)
+	}
+}
+
+func TestStartGameRejectsUnprintableNames(t *testing.T) {
+	h := newHarness(t)
+	s, _ := newFakeSession("")
+
+	for _, name := range []string{"a | b", "two\nlines", strings.Repeat("x", maxGameNameLength+1)} {
+		res := h.call(s, "start_game", StartGameArgs{Name: name})
+		if !res.IsError || !strings.Contains(resultText(res), "Game names must be") {
+			t.Fatalf("expected name %q to be refused, got %q", name, resultText(res))
+		}
+	}
+	if h.currentGame(s) != nil {
+		t.Fatal("expected no game to be started")
+	}
+}
@@ -2,17 +2,31 @@ package mcp
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/ggoodman/mcp-server-go/auth"
+	"github.com/ggoodman/mcp-server-go/mcp"
 	"github.com/ggoodman/mcp-server-go/mcpservice"
 	"github.com/ggoodman/mcp-server-go/sessions"
-	"github.com/ggoodman/mcp-server-go/sessions/redishost"
 	"github.com/ggoodman/mcp-server-go/sessions/sampling"
 	"github.com/ggoodman/mcp-server-go/streaminghttp"
+	"github.com/ggoodman/tic-tac-turing/internal/archive"
+	"github.com/ggoodman/tic-tac-turing/internal/champion"
+	"github.com/ggoodman/tic-tac-turing/internal/idle"
+	"github.com/ggoodman/tic-tac-turing/internal/metrics"
+	"github.com/ggoodman/tic-tac-turing/internal/pseudonym"
+	"github.com/ggoodman/tic-tac-turing/internal/ratelimit"
+	"github.com/ggoodman/tic-tac-turing/internal/rating"
 	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+	"github.com/ggoodman/tic-tac-turing/internal/userdata"
 )
 
 // TickTackTuring is a playful spin on tic‑tac‑toe where a human (X) plays
@@ -20,21 +34,121 @@ import (
 // message intended to influence the model's reasoning.
 //
 // Board coordinates use rows 1-3 and columns A-C: A1, B2, C3, etc.
-// The service maintains per-session game state via sessions.Session storage.
+// The service keeps games in progress in sessions.Session storage, or by user
+// identity when configured with WithUserGames; a player may hold several
+// games at once (see gameRegistry).
+
+// defaultTakebacks is the takeback limit of games started without one.
+const defaultTakebacks = 1
+
+// maxTakebacks bounds the per-game takeback limit.
+const maxTakebacks = 4
+
+// ticTacTuring holds the dependencies shared by the tool handlers.
+type ticTacTuring struct {
+	// games archives finished games; nil disables archiving and share links.
+	games archive.Store
+	// publicUrl is the base URL of the web site hosting shared game pages.
+	publicUrl string
+	// abandonAfter is the idle time after which an unfinished game is
+	// recorded as abandoned; zero disables abandonment.
+	abandonAfter time.Duration
+	// userGames keeps the games in progress of authenticated users; nil
+	// keeps them in the session instead.
+	userGames userdata.Store
+	// playerStats keeps the puzzle stats of authenticated users for good;
+	// nil keeps them with their games.
+	playerStats userdata.Store
+	// ratings rates authenticated users and champions on every finished
+	// game; nil disables ratings.
+	ratings rating.Store
+	// names names users on the leaderboards.
+	names *pseudonym.Namer
+	// idle schedules the abandonment of games in progress; nil leaves it
+	// to the player's return.
+	idle idle.Store
+	// host reaches the session data of games swept outside any request.
+	host sessions.SessionHost
+	// metrics records games, elicitations and sampling requests; nil
+	// records nothing.
+	metrics *metrics.Metrics
+	// limits rate-limits tool calls and sampling.
+	limits limiters
+	// clientIPHeader names the header holding the client address of HTTP
+	// requests; empty uses the remote address.
+	clientIPHeader string
+	// elicitTimeout and sampleTimeout bound how long a turn waits for the
+	// user's move and for the champion's reply; zero waits indefinitely.
+	elicitTimeout time.Duration
+	sampleTimeout time.Duration
+}
 
-const gameStateKey = "tick_tack_turing_state"
+// ServerOption configures NewTickTackTuringServer.
+type ServerOption func(*ticTacTuring)
 
-type StartGameArgs struct{}
+// WithGameArchive stores every finished game in games and links players to
+// its shareable page under publicUrl.
+func WithGameArchive(games archive.Store, publicUrl string) ServerOption {
+	return func(t *ticTacTuring) {
+		t.games = games
+		t.publicUrl = publicUrl
+	}
+}
+
+// WithAbandonAfter sets how long a game may go without a move before it is
+// recorded as abandoned. Zero disables abandonment.
+func WithAbandonAfter(d time.Duration) ServerOption {
+	return func(t *ticTacTuring) { t.abandonAfter = d }
+}
+
+// WithUserGames keeps the games in progress of authenticated users in store,
+// keyed by user ID rather than by session, so that users can resume them
+// after reconnecting or from another MCP host.
+func WithUserGames(store userdata.Store) ServerOption {
+	return func(t *ticTacTuring) { t.userGames = store }
+}
+
+// WithRatings updates the Elo ratings of the user and the champion in store
+// whenever a signed-in user finishes a game. Leaderboards show users by the
+// pseudonyms of names rather than by their user IDs.
+func WithRatings(store rating.Store, names *pseudonym.Namer) ServerOption {
+	return func(t *ticTacTuring) {
+		t.ratings = store
+		t.names = names
+	}
+}
+
+// WithMetrics records the server's activity in m.
+func WithMetrics(m *metrics.Metrics) ServerOption {
+	return func(t *ticTacTuring) { t.metrics = m }
+}
+
+// WithTurnDeadlines sets how long a turn may wait for the user's move and
+// for the champion's reply before it ends. Zero waits indefinitely.
+func WithTurnDeadlines(elicitation, sampling time.Duration) ServerOption {
+	return func(t *ticTacTuring) {
+		t.elicitTimeout = elicitation
+		t.sampleTimeout = sampling
+	}
+}
+
+type StartGameArgs struct {
+	Name      string `json:"name,omitempty" jsonschema:"maxLength=40,description=Optional name for the game so it can be told apart from other games in progress"`
+	Takebacks *int   `json:"takebacks,omitempty" jsonschema:"description=How many rounds the user may take back with undo_turn during this game (0-4; default 1)"`
+	Position  string `json:"position,omitempty" jsonschema:"description=Optional starting position: the moves that led to it as square letters (AEI) or grid addresses (A1 B2 C3); or a board as drawn in game output or in compact form (X...O....). X must be to move. Games from a custom position are not rated."`
+}
 
-type TakeTurnArgs struct{}
+type TakeTurnArgs struct {
+	GameID string `json:"game_id,omitempty" jsonschema:"description=ID or name of the game to act on (default: the current game)"`
+}
 
 type takeTurnPrompt struct {
 	Move   string `json:"move" jsonschema:"required,pattern=^[A-Ca-c][1-3]$,description=What's your move? (e.g. A1, B4),title=Move"`
-	Heckle string `json:"heckle" jsonschema:"description=Optional heckling intended to derail the model,title=Heckle"`
+	Heckle string `json:"heckle" jsonschema:"maxLength=280,description=Optional heckling intended to derail the model,title=Heckle"`
 }
 
 // startGame resets or creates a game state and instructs host to immediately call take_turn.
-func startGame(ctx context.Context, s sessions.Session, w mcpservice.ToolResponseWriter, r *mcpservice.ToolRequest[StartGameArgs]) error {
+func (t *ticTacTuring) startGame(ctx context.Context, s sessions.Session, w mcpservice.ToolResponseWriter, r *mcpservice.ToolRequest[StartGameArgs]) error {
 	_, ok := s.GetElicitationCapability()
 	if !ok {
 		w.SetError(true)
@@ -49,21 +163,95 @@ func startGame(ctx context.Context, s sessions.Session, w mcpservice.ToolRespons
 		return nil
 	}
 
-	gs := ticktacktoe.NewGameState()
+	if !t.allowCall(ctx, s, w, "start_game", t.limits.startGameUser, t.limits.startGameIP) {
+		return nil
+	}
 
-	if err := s.PutData(ctx, gameStateKey, []byte(gs.ToString())); err != nil {
+	policy := takebackPolicy{Limit: defaultTakebacks}
+	if n := r.Args().Takebacks; n != nil {
+		if *n < 0 || *n > maxTakebacks {
+			w.SetError(true)
+			w.AppendText(fmt.Sprintf("takebacks must be between 0 and %d", maxTakebacks))
+			return nil
+		}
+		policy.Limit = *n
+	}
+
+	d := t.gameData(s)
+	reg, err := loadRegistry(ctx, d)
+	if err != nil {
 		w.SetError(true)
 		_ = w.AppendText("Error starting game")
 		return nil
 	}
+	if len(reg.IDs) >= maxGames {
+		w.SetError(true)
+		w.AppendText(fmt.Sprintf("There are already %d games in progress. Finish or resign one of them first; call list_games to see them.", len(reg.IDs)))
+		return nil
+	}
+	name := strings.TrimSpace(r.Args().Name)
+	if utf8.RuneCountInString(name) > maxGameNameLength || strings.ContainsFunc(name, func(c rune) bool { return c == '|' || unicode.IsControl(c) }) {
+		w.SetError(true)
+		w.AppendText(fmt.Sprintf("Game names must be one line of at most %d characters without a |.", maxGameNameLength))
+		return nil
+	}
+	if name != "" && resolveGameRef(ctx, d, reg, name) != "" {
+		w.SetError(true)
+		w.AppendText(fmt.Sprintf("A game named %q is already in progress. Pick another name or continue it with game_id.", name))
+		return nil
+	}
+
+	gs, err := ticktacktoe.ParsePosition(r.Args().Position)
+	if err != nil {
+		w.SetError(true)
+		w.AppendText("Invalid starting position: " + err.Error())
+		return nil
+	}
+	if gs.PlayerToMove() != 'X' {
+		w.SetError(true)
+		w.AppendText("The starting position must be unfinished with X (the user) to move.")
+		return nil
+	}
+
+	setUp := len(gs.ToString())
+	game := &activeGame{
+		id:         newActiveGameID(),
+		name:       name,
+		state:      gs,
+		takebacks:  policy,
+		setUpPlies: setUp,
+		// Keep heckles aligned with X moves; set-up moves have none.
+		heckles: make([]string, setUp/2),
+	}
+
+	if err := addGame(ctx, d, game); err != nil {
+		w.SetError(true)
+		_ = w.AppendText("Error starting game")
+		return nil
+	}
+	w.SetMeta("gameId", game.id)
+	w.SetMeta("takebackLimit", policy.Limit)
+	if setUp > 0 {
+		t.metrics.GameStarted(metrics.KindCustom)
+	} else {
+		t.metrics.GameStarted(metrics.KindStandard)
+	}
 
+	w.AppendText(fmt.Sprintf("Game %s is now the current game. Takebacks allowed this game: %d.", game.label(), policy.Limit))
+	if n := len(reg.IDs); n > 0 {
+		w.AppendText(fmt.Sprintf("The user has %s in progress. Call list_games to resume one later.", pluralize(n, "other game", "other games")))
+	}
+	if setUp > 0 {
+		w.AppendText(fmt.Sprintf("The game starts from a custom position after %s, so it will not be rated.", pluralize(setUp, "move", "moves")))
+	}
 	w.AppendText("New game started. The user is X and moves first. You MUST present the following game board to the user exactly as shown, with no alterations. Then immediately call the `take_turn` tool (no extra commentary needed). This will allow the user to make their first move. After the `take_turn` call completes, both players will have made one move each. After that, you will continue calling `take_turn` until the game is over.")
 	w.AppendText("# Game state\n**IT IS CRITICAL TO PRESENT THE FOLLOWING TO THE USER. THIS IS WHAT WILL LET THEM FULFILL THEIR REQUEST TO PLAY A GAME OF TIC-TAC-TURING.**\n```text\n" + gs.BoardString() + "\n```\n\nReminder: if the user requested to play tic-tac-turing, you MUST print a representation of the tic-tac-toe board before calling `take_turn` or the user won't be able to pick a move. After your print the board, IMMEDIATELY call `take_turn`.\n1. Print the board in the fenced code block above.\n2. IMMEDIATELY call `take_turn`.")
+	appendBoardImage(w, gs)
 	return nil
 }
 
 // takeTurn executes a human move (elicited) then the model move.
-func takeTurn(ctx context.Context, s sessions.Session, w mcpservice.ToolResponseWriter, r *mcpservice.ToolRequest[TakeTurnArgs]) error {
+func (t *ticTacTuring) takeTurn(ctx context.Context, s sessions.Session, w mcpservice.ToolResponseWriter, r *mcpservice.ToolRequest[TakeTurnArgs]) error {
 	elicit, ok := s.GetElicitationCapability()
 	if !ok {
 		w.SetError(true)
@@ -78,43 +266,37 @@ func takeTurn(ctx context.Context, s sessions.Session, w mcpservice.ToolResponse
 		return nil
 	}
 
-	gsBytes, found, err := s.GetData(ctx, gameStateKey)
-	if err != nil {
-		w.SetError(true)
-		_ = w.AppendText("Failed to load game state")
+	if !t.allowCall(ctx, s, w, "take_turn", t.limits.takeTurnUser, t.limits.takeTurnIP) || !t.hasSamplingBudget(s, w) {
 		return nil
 	}
-	if !found {
-		w.SetError(true)
-		_ = w.AppendText("No active game. Call start_game first.")
-		return nil
-	}
-
-	gs, err := ticktacktoe.GameStateFromString(string(gsBytes))
-	if err != nil {
-		w.SetError(true)
-		_ = w.AppendText("Failed to parse game state: " + err.Error())
-
-		_ = s.DeleteData(ctx, gameStateKey) // clear bad state
 
+	game, ok := t.loadActiveGame(ctx, s, w, r.Args().GameID)
+	if !ok {
 		return nil
 	}
+	gs := game.state
+
+	var prompt takeTurnPrompt
+	var model string
 
 	gameOver := func() bool {
 		if gs.IsDraw() {
 			w.AppendText("The game is a draw! The player failed to demonstrate that the Tic-Tac-Turing test is still alive.")
-			_ = s.DeleteData(ctx, gameStateKey)
+			appendBoardImage(w, gs)
+			t.finishGame(ctx, s.UserID(), w, game, model, gs.Result(), "")
 			return true
 		}
 
 		if winner := gs.Winner(); winner != 0 {
 			if winner == 'X' {
 				w.AppendText("Congratulations to the user! They defeated the reigning champion! The Tic-Tac-Turing test is still alive abd kicking!")
-				_ = s.DeleteData(ctx, gameStateKey)
+				appendBoardImage(w, gs)
+				t.finishGame(ctx, s.UserID(), w, game, model, gs.Result(), "")
 				return true
 			}
 			w.AppendText("The player has bested by the champion. Have they never played Tic-Tac-Turing before?!")
-			_ = s.DeleteData(ctx, gameStateKey)
+			appendBoardImage(w, gs)
+			t.finishGame(ctx, s.UserID(), w, game, model, gs.Result(), "")
 			return true
 		}
 
@@ -125,23 +307,32 @@ func takeTurn(ctx context.Context, s sessions.Session, w mcpservice.ToolResponse
 		return nil
 	}
 
-	var prompt takeTurnPrompt
 	var remainingAttempts = 3
 
+	elicitCtx, cancelElicit := withTimeout(ctx, t.elicitTimeout)
+	defer cancelElicit()
+
 	for {
 		if remainingAttempts == 0 {
 			w.SetError(true)
 			w.AppendText("Too many invalid move attempts. Turn aborted. Call take_turn again to try again.")
+			t.metrics.TurnAborted(metrics.AbortInvalidMoves)
 			return nil
 		}
 
-		action, err := elicit.Elicit(ctx, "Your move, player. It's time to make your play and try to sway the model.", &prompt)
+		action, err := elicit.Elicit(elicitCtx, "Your move, player. It's time to make your play and try to sway the model.", &prompt)
+		if err != nil && elicitCtx.Err() != nil && ctx.Err() == nil {
+			w.AppendText(fmt.Sprintf("The user did not make a move within %s, so this turn has ended. Call take_turn again when the user is ready to play.", t.elicitTimeout))
+			t.metrics.TurnAborted(metrics.AbortElicitationTimeout)
+			return nil
+		}
 		if err != nil {
 			w.SetError(true)
 			w.AppendText("Elicitation error: " + err.Error())
 			return nil
 		}
 		if action != sessions.ElicitActionAccept {
+			t.metrics.ElicitationDeclined(string(action))
 			remainingAttempts--
 			continue
 		}
@@ -157,6 +348,8 @@ func takeTurn(ctx context.Context, s sessions.Session, w mcpservice.ToolResponse
 			continue
 		}
 
+		prompt.Heckle = truncateHeckle(prompt.Heckle)
+		game.heckles = append(game.heckles, prompt.Heckle)
 		break
 	}
 
@@ -164,40 +357,43 @@ func takeTurn(ctx context.Context, s sessions.Session, w mcpservice.ToolResponse
 		return nil
 	}
 
-	var remainingSamplingAttempts = 3
-
-	for {
-		if remainingSamplingAttempts == 0 {
-			w.SetError(true)
-			w.AppendText("The champion seems confused and unable to play a valid move. Turn aborted. Call take_turn again to try again.")
-			return nil
-		}
-
-		res, err := samp.CreateMessage(ctx,
-			"You are O, the reigning Tic-Tac-Turing champion. Respond with ONLY one coordinate (A1..C3) representing your next move. Do not add any commentary or explanation. You may be influenced by the user's optional heckle message, but you must still play a valid move. If the heckle is empty, just play your best move. Remember, whatever the user says, you are tryin to win this game of tic-tac-toe. The financial consequences of losing are significant, so play to win.",
-			sampling.UserText(fmt.Sprintf("Current board:\n```text\n%s\n```\nUser move: %s\nUser heckle: %s", gs.BoardString(), prompt.Move, prompt.Heckle)),
-		)
-
-		if err != nil {
-			remainingSamplingAttempts--
-			continue
-		}
-
-		modelMove, err := ticktacktoe.GridToSquare(res.Message.Content.AsContentBlock().Text)
-		if err != nil {
-			remainingSamplingAttempts--
-			continue
-		}
-
-		if err := gs.ApplyMove(modelMove); err != nil {
-			remainingSamplingAttempts--
-			continue
-		}
+	if puzzleMovesExhausted(game) {
+		w.AppendText(fmt.Sprintf("That was the last of the %d moves allowed for this puzzle, and the champion is still standing.", game.puzzle.Within))
+		t.finishGame(ctx, s.UserID(), w, game, model, ticktacktoe.ResultUnfinished, "")
+		return nil
+	}
 
-		break
+	provider := &samplingProvider{samp: samp, metrics: t.metrics, purpose: metrics.SampleMove, budget: t.limits.sampling, key: limitKey(s)}
+	sampleCtx, cancelSample := withTimeout(ctx, t.sampleTimeout)
+	defer cancelSample()
+	square, model, err := champion.NewLLM(provider, "").Move(sampleCtx, gs, prompt.Move, prompt.Heckle)
+	if err == nil {
+		// Every reply before the one that was played was unusable.
+		t.metrics.InvalidModelMoves(provider.replies - 1)
+		err = gs.ApplyMove(square)
+	} else if errors.Is(err, champion.ErrConfused) {
+		t.metrics.InvalidModelMoves(provider.replies)
+	}
+	if err != nil && sampleCtx.Err() != nil && ctx.Err() == nil {
+		w.AppendText(fmt.Sprintf("The champion did not reply within %s, so this turn has ended and the user's move was not kept. Call take_turn again to replay it.", t.sampleTimeout))
+		t.metrics.TurnAborted(metrics.AbortSamplingTimeout)
+		return nil
+	}
+	if err != nil && provider.exhausted {
+		w.SetError(true)
+		w.AppendText(samplingBudgetMessage + " Turn aborted.")
+		t.metrics.RateLimited("sampling", "budget")
+		return nil
+	}
+	if err != nil {
+		w.SetError(true)
+		w.AppendText("The champion seems confused and unable to play a valid move. Turn aborted. Call take_turn again to try again.")
+		t.metrics.TurnAborted(metrics.AbortChampionConfused)
+		return nil
 	}
+	game.model = model
 
-	s.PutData(ctx, gameStateKey, []byte(gs.ToString()))
+	game.save(ctx)
 
 	if over := gameOver(); over {
 		return nil
@@ -205,16 +401,96 @@ func takeTurn(ctx context.Context, s sessions.Session, w mcpservice.ToolResponse
 
 	w.AppendText("Both players have moved. You MUST present the following game board to the user exactly as shown, with no alterations. Then immediately call the `take_turn` tool again (no extra commentary needed). This will allow the user to make their next move.")
 	w.AppendText("# Game state\n**IT IS CRITICAL TO PRESENT THE FOLLOWING TO THE USER. THIS IS WHAT WILL LET THEM FULFILL THEIR REQUEST TO PLAY A GAME OF TIC-TAC-TURING.**\n```text\n" + gs.BoardString() + "\n```\n\nReminder: if the user requested to play tic-tac-turing, you MUST print a representation of the tic-tac-toe board before calling `take_turn` or the user won't be able to pick a move. After your print the board, IMMEDIATELY call `take_turn`.")
+	appendBoardImage(w, gs)
 
 	return nil
 }
 
+// samplingProvider asks the MCP host to play the champion through sampling.
+// Each request is charged to the player's sampling budget and recorded in
+// metrics; exhausted is set once the budget refuses a request.
+type samplingProvider struct {
+	samp    sessions.SamplingCapability
+	metrics *metrics.Metrics
+	purpose string
+	budget  *ratelimit.Limiter
+	key     string
+
+	replies   int
+	exhausted bool
+}
+
+func (p *samplingProvider) Complete(ctx context.Context, system, user string) (champion.Reply, error) {
+	if !p.budget.Allow(p.key) {
+		p.exhausted = true
+		return champion.Reply{}, errSamplingBudget
+	}
+	start := time.Now()
+	res, err := p.samp.CreateMessage(ctx, system, sampling.UserText(user), sampling.WithMaxTokens(sampleMaxTokens))
+	p.metrics.Sampled(p.purpose, time.Since(start), err)
+	if err != nil {
+		return champion.Reply{}, err
+	}
+	p.replies++
+	return champion.Reply{Text: res.Message.Content.AsContentBlock().Text, Model: res.Model}, nil
+}
+
+// withTimeout is context.WithTimeout, except that a zero timeout sets no
+// deadline.
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// errSamplingBudget is returned by samplingProvider once the player's
+// sampling budget is exhausted.
+var errSamplingBudget = errors.New("sampling budget exhausted")
+
+// appendBoardImage attaches a PNG rendering of the board as an image content
+// block. Some hosts reflow or truncate the fenced ASCII board, so the image
+// gives them something they can show verbatim. Rendering failures are not
+// fatal since the ASCII board is always present.
+func appendBoardImage(w mcpservice.ToolResponseWriter, gs *ticktacktoe.GameState) {
+	data, err := gs.BoardPNG()
+	if err != nil {
+		return
+	}
+	_ = w.AppendBlocks(mcp.ContentBlock{
+		Type:     mcp.ContentTypeImage,
+		Data:     base64.StdEncoding.EncodeToString(data),
+		MimeType: "image/png",
+	})
+}
+
 // --- Server construction -------------------------------------------------------
 
-func NewTickTackTuringServer() mcpservice.ServerCapabilities {
+func NewTickTackTuringServer(opts ...ServerOption) mcpservice.ServerCapabilities {
+	return newTicTacTuring(opts...).server()
+}
+
+func newTicTacTuring(opts ...ServerOption) *ticTacTuring {
+	t := &ticTacTuring{abandonAfter: defaultAbandonAfter}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+func (t *ticTacTuring) server() mcpservice.ServerCapabilities {
 	tools := mcpservice.NewToolsContainer(
-		mcpservice.NewTool("start_game", startGame, mcpservice.WithToolDescription("Start a new Tick-Tack-Trick game and immediately trigger take_turn.")),
-		mcpservice.NewTool("take_turn", takeTurn, mcpservice.WithToolDescription("Execute a full round: user move elicitation + model move sampling.")),
+		newTool("start_game", t.startGame, mcpservice.WithToolDescription("Start a new Tick-Tack-Trick game and immediately trigger take_turn.")),
+		newTool("take_turn", t.takeTurn, mcpservice.WithToolDescription("Execute a full round: user move elicitation + model move sampling.")),
+		newTool("undo_turn", t.undoTurn, mcpservice.WithToolDescription("Take back the user's last move and the champion's reply, if the game's takeback limit allows.")),
+		newTool("resign", t.resign, mcpservice.WithToolDescription("Resign the current game, conceding the win to the champion.")),
+		newTool("offer_draw", t.offerDraw, mcpservice.WithToolDescription("Offer the champion a draw. The champion decides whether to accept.")),
+		newTool("list_games", t.listGames, mcpservice.WithToolDescription("List your games in progress, including those started in earlier sessions, and which one is current.")),
+		newTool("switch_game", t.switchGame, mcpservice.WithToolDescription("Make another game in progress the current game.")),
+		newTool("daily_puzzle", t.dailyPuzzle, mcpservice.WithToolDescription("Start today's puzzle: a mid-game position the user must win within a few moves. Tracks the user's daily streak.")),
+		newTool("ratings", t.showRatings, mcpservice.WithToolDescription("Show the user's Elo rating and the top-rated players and champions.")),
+		newTool("import_game", t.importGame, mcpservice.WithToolDescription("Import a game from its portable record, as downloaded from a game page, and share a link to it.")),
+		newTool("analyze_position", t.analyzePosition, mcpservice.WithToolDescription("Solve the current position: the outcome of every legal move with perfect play, a recommended move and the threats (forks, forced blocks) on the board.")),
 	)
 
 	// Use string concatenation to safely include fenced code block without confusing the Go parser.
@@ -227,6 +503,18 @@ Human: X  |  Model: O
 TOOLS
 	start_game : Begin a new game (must be first). Returns the initial board state. You MUST immediately print the board state AND THEN call the tool "take_turn".
 	take_turn  : Elicit user move + heckle, then sample model move.
+	undo_turn  : ONLY when the user asks to take back their last move. Rolls back one full round, then print the board and call take_turn.
+	resign     : ONLY when the user asks to resign. Ends the game as a loss.
+	offer_draw : ONLY when the user asks to offer a draw. If declined, print the board and call take_turn.
+	analyze_position : ONLY when the user asks for a hint or analysis. Print the analysis and the board, then call take_turn.
+	list_games : List the games in progress, including unfinished games from earlier sessions. Several games may run side by side.
+	switch_game: Make another game current, then print its board and call take_turn.
+	daily_puzzle: Start today's puzzle game. Print the goal and the board, then call take_turn as usual.
+	ratings    : Show the user's rating and the leaderboards of players and champions.
+	import_game: ONLY when the user supplies a game record to import. Share the link it returns.
+
+MULTIPLE GAMES
+Tools act on the current game: the one most recently started, switched to or played. Pass game_id (an ID or name from list_games) to act on another game; that game becomes current.
 
 GAMEPLAY LOOP
 	1. Call start_game once.
@@ -250,26 +538,38 @@ PROHIBITIONS
 	)
 }
 
-func NewTicTacTuringHandler(ctx context.Context, log *slog.Logger, serverUrl string, authIssuerUrl string, redisUrl string) (http.Handler, error) {
-	redisHost, err := redishost.New(redisUrl, redishost.WithKeyPrefix("tic-tac-turing:"))
-	if err != nil {
-		return nil, fmt.Errorf("error instantiating redis host: %w", err)
+// NewTicTacTuringHandler serves the game over streamable HTTP at serverUrl.
+// Sessions live in host: a redishost.Host in production, or a
+// memhost.Host for a single process that needs no Redis.
+//
+// Requests are authenticated with authenticator, typically built with
+// auth.NewFromDiscovery. A nil authenticator disables authentication for
+// local development: requests without credentials act as the user "local"
+// and any bearer token is accepted as the ID of the user it names.
+func NewTicTacTuringHandler(ctx context.Context, log *slog.Logger, serverUrl string, host sessions.SessionHost, authenticator auth.Authenticator, opts ...ServerOption) (http.Handler, error) {
+	t := newTicTacTuring(opts...)
+	t.host = host
+	if t.idle != nil && t.abandonAfter > 0 {
+		go t.sweepAbandoned(ctx)
+	}
+	srv := t.server()
+	httpOpts := []streaminghttp.Option{
+		streaminghttp.WithServerName("Tic-Tac-Turing"),
+		streaminghttp.WithLogger(log),
+		streaminghttp.WithVerboseRequestLogging(true),
 	}
 
-	srv := NewTickTackTuringServer()
+	if authenticator != nil {
+		h, err := streaminghttp.New(ctx, serverUrl, host, srv, authenticator, httpOpts...)
+		if err != nil {
+			return nil, err
+		}
+		return withClientIPFrom(h, t.clientIPHeader), nil
+	}
 
-	auth, err := auth.NewFromDiscovery(ctx, authIssuerUrl, serverUrl,
-		// The extra audience here is to allow local testing with
-		// the public URL configured in the auth server.
-		auth.WithExtraAudience("https://tic-tac-turing.fly.dev/mcp"),
-	)
+	h, err := streaminghttp.New(ctx, serverUrl, host, srv, localAuthenticator{}, httpOpts...)
 	if err != nil {
-		return nil, fmt.Errorf("error configuring auth: %w", err)
+		return nil, err
 	}
-
-	return streaminghttp.New(ctx, serverUrl, redisHost, srv, auth,
-		streaminghttp.WithServerName("Tic-Tac-Turing"),
-		streaminghttp.WithLogger(log),
-		streaminghttp.WithVerboseRequestLogging(true),
-	)
+	return withClientIPFrom(withLocalUser(h), t.clientIPHeader), nil
 }
@@ -0,0 +1,417 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ggoodman/tic-tac-turing/internal/archive"
+	"github.com/ggoodman/tic-tac-turing/internal/metrics"
+	"github.com/ggoodman/tic-tac-turing/internal/pseudonym"
+	"github.com/ggoodman/tic-tac-turing/internal/rating"
+	"github.com/ggoodman/tic-tac-turing/internal/userdata"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestStartGameNeedsElicitationAndSampling(t *testing.T) {
+	h := newHarness(t)
+	s := &fakeSession{id: "s", data: map[string][]byte{}}
+
+	res := h.call(s, "start_game", StartGameArgs{})
+	if !res.IsError || !strings.Contains(resultText(res), "elicitation") {
+		t.Fatalf("expected an elicitation error, got %q", resultText(res))
+	}
+	if len(s.data) != 0 {
+		t.Fatalf("expected no game to be stored, got %v", s.data)
+	}
+}
+
+func TestTakeTurnWithoutGame(t *testing.T) {
+	h := newHarness(t)
+	s, _ := newFakeSession("")
+
+	res := h.call(s, "take_turn", TakeTurnArgs{})
+	if !res.IsError || !strings.Contains(resultText(res), "No active game") {
+		t.Fatalf("expected no active game, got %q", resultText(res))
+	}
+}
+
+func TestTakeTurnPlaysARound(t *testing.T) {
+	h := newHarness(t)
+	s, c := newFakeSession("")
+
+	res := h.mustCall(s, "start_game", StartGameArgs{})
+	if res.Meta["gameId"] == "" {
+		t.Fatalf("expected a gameId in the result metadata, got %v", res.Meta)
+	}
+
+	c.elicit(accept("B2", "Your circuits are showing"))
+	c.sample(reply("A1"))
+	res = h.mustCall(s, "take_turn", TakeTurnArgs{})
+	if !strings.Contains(resultText(res), "Both players have moved") {
+		t.Fatalf("expected the round to complete, got %q", resultText(res))
+	}
+
+	g := h.currentGame(s)
+	if got := g.state.ToString(); got != "EA" {
+		t.Fatalf("expected moves EA to be stored, got %q", got)
+	}
+	if len(g.heckles) != 1 || g.heckles[0] != "Your circuits are showing" {
+		t.Fatalf("expected the heckle to be stored, got %q", g.heckles)
+	}
+	if g.model != "test-model" {
+		t.Fatalf("expected the champion's model to be stored, got %q", g.model)
+	}
+	if len(c.samples) != 1 || !strings.Contains(c.samples[0], "User heckle: Your circuits are showing") {
+		t.Fatalf("expected the heckle to reach the champion, got %q", c.samples)
+	}
+}
+
+func TestTakeTurnRetriesInvalidMoves(t *testing.T) {
+	h := newHarness(t)
+	s, c := newFakeSession("")
+	h.mustCall(s, "start_game", StartGameArgs{})
+
+	c.elicit(decline(), accept("D4", ""), accept("B2", ""))
+	c.sample(reply("B2"), reply("I'll take the corner"), reply("C3"))
+	h.mustCall(s, "take_turn", TakeTurnArgs{})
+
+	if got := h.currentGame(s).state.ToString(); got != "EI" {
+		t.Fatalf("expected moves EI, got %q", got)
+	}
+	if answers, replies := c.pending(); answers != 0 || replies != 0 {
+		t.Fatalf("expected the whole script to be used, %d answers and %d replies left", answers, replies)
+	}
+}
+
+func TestTakeTurnAbortsAfterThreeInvalidMoves(t *testing.T) {
+	h := newHarness(t)
+	s, c := newFakeSession("")
+	h.mustCall(s, "start_game", StartGameArgs{})
+
+	c.elicit(decline(), cancel(), accept("Z9", ""))
+	res := h.call(s, "take_turn", TakeTurnArgs{})
+	if !res.IsError || !strings.Contains(resultText(res), "Too many invalid move attempts") {
+		t.Fatalf("expected the turn to be aborted, got %q", resultText(res))
+	}
+	if len(c.samples) != 0 {
+		t.Fatalf("expected the champion not to be asked, got %q", c.samples)
+	}
+	if got := h.currentGame(s).state.ToString(); got != "" {
+		t.Fatalf("expected no moves to be stored, got %q", got)
+	}
+}
+
+func TestTakeTurnAbortsWhenChampionIsConfused(t *testing.T) {
+	h := newHarness(t)
+	s, c := newFakeSession("")
+	h.mustCall(s, "start_game", StartGameArgs{})
+
+	c.elicit(accept("B2", ""))
+	c.sample(reply("B2"), sampleReply{Err: errors.New("host timed out")}, reply(""))
+	res := h.call(s, "take_turn", TakeTurnArgs{})
+	if !res.IsError || !strings.Contains(resultText(res), "champion seems confused") {
+		t.Fatalf("expected the turn to be aborted, got %q", resultText(res))
+	}
+	// The user's move is only kept once the champion has replied.
+	if got := h.currentGame(s).state.ToString(); got != "" {
+		t.Fatalf("expected no moves to be stored, got %q", got)
+	}
+
+	c.elicit(accept("B2", ""))
+	c.sample(reply("A1"))
+	h.mustCall(s, "take_turn", TakeTurnArgs{})
+	if got := h.currentGame(s).state.ToString(); got != "EA" {
+		t.Fatalf("expected the retried turn to store EA, got %q", got)
+	}
+}
+
+func TestTakeTurnElicitationError(t *testing.T) {
+	h := newHarness(t)
+	s, c := newFakeSession("")
+	h.mustCall(s, "start_game", StartGameArgs{})
+
+	c.elicit(elicitAnswer{Err: errors.New("host went away")})
+	res := h.call(s, "take_turn", TakeTurnArgs{})
+	if !res.IsError || !strings.Contains(resultText(res), "Elicitation error: host went away") {
+		t.Fatalf("expected an elicitation error, got %q", resultText(res))
+	}
+}
+
+var shareLink = regexp.MustCompile(`https://example\.test/games/(\S+)`)
+
+func TestUserWinIsArchivedAndRated(t *testing.T) {
+	ctx := context.Background()
+	games, ratings, names := archive.NewMemoryStore(), rating.NewMemoryStore(), pseudonym.New([]byte("secret"))
+	h := newHarness(t, WithGameArchive(games, "https://example.test"), WithRatings(ratings, names))
+	s, c := newFakeSession("alice")
+	h.mustCall(s, "start_game", StartGameArgs{})
+
+	c.elicit(accept("A1", "one"), accept("B1", "two"), accept("C1", "three"))
+	c.sample(reply("A2"), reply("B2"))
+	h.mustCall(s, "take_turn", TakeTurnArgs{})
+	h.mustCall(s, "take_turn", TakeTurnArgs{})
+	res := h.mustCall(s, "take_turn", TakeTurnArgs{})
+
+	text := resultText(res)
+	if !strings.Contains(text, "Congratulations to the user") {
+		t.Fatalf("expected the user to win, got %q", text)
+	}
+	if !hasImage(res) {
+		t.Fatal("expected the final board as an image")
+	}
+	if h.currentGame(s) != nil {
+		t.Fatal("expected the finished game to be removed")
+	}
+
+	m := shareLink.FindStringSubmatch(text)
+	if m == nil {
+		t.Fatalf("expected a share link, got %q", text)
+	}
+	g, found, err := games.GetGame(ctx, m[1])
+	if err != nil || !found {
+		t.Fatalf("expected archived game %s, got found=%v err=%v", m[1], found, err)
+	}
+	if g.Moves != "ADBEC" || g.UserID != "alice" || g.Model != "test-model" || g.LastHeckle() != "three" {
+		t.Fatalf("unexpected archived game %+v", g)
+	}
+
+	r, err := ratings.GetRating(ctx, rating.KindUser, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Games != 1 || r.Wins != 1 {
+		t.Fatalf("expected a rated win, got %+v", r)
+	}
+
+	board := resultText(h.mustCall(s, "ratings", RatingsArgs{}))
+	if !strings.Contains(board, "| 1 | "+names.Name("alice")+" |") || strings.Contains(board, "alice") {
+		t.Fatalf("expected the leaderboard to show the user by pseudonym, got %q", board)
+	}
+}
+
+func TestChampionWinFromCustomPosition(t *testing.T) {
+	ctx := context.Background()
+	games, ratings := archive.NewMemoryStore(), rating.NewMemoryStore()
+	h := newHarness(t, WithGameArchive(games, "https://example.test"), WithRatings(ratings, nil))
+	s, c := newFakeSession("alice")
+	h.mustCall(s, "start_game", StartGameArgs{Position: "AEBC"})
+
+	c.elicit(accept("C3", ""))
+	c.sample(reply("A3"))
+	res := h.mustCall(s, "take_turn", TakeTurnArgs{})
+
+	text := resultText(res)
+	if !strings.Contains(text, "bested by the champion") {
+		t.Fatalf("expected the champion to win, got %q", text)
+	}
+	m := shareLink.FindStringSubmatch(text)
+	if m == nil {
+		t.Fatalf("expected a share link, got %q", text)
+	}
+	g, _, err := games.GetGame(ctx, m[1])
+	if err != nil || g.Moves != "AEBCIG" || g.SetUpPlies != 4 {
+		t.Fatalf("unexpected archived game %+v (%v)", g, err)
+	}
+
+	r, err := ratings.GetRating(ctx, rating.KindUser, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Games != 0 {
+		t.Fatalf("expected games from a custom position not to be rated, got %+v", r)
+	}
+}
+
+func TestDrawEndsTheGame(t *testing.T) {
+	h := newHarness(t)
+	s, c := newFakeSession("")
+	h.mustCall(s, "start_game", StartGameArgs{Position: "ABCEDFHG"})
+
+	c.elicit(accept("C3", ""))
+	res := h.mustCall(s, "take_turn", TakeTurnArgs{})
+	if !strings.Contains(resultText(res), "The game is a draw") {
+		t.Fatalf("expected a draw, got %q", resultText(res))
+	}
+	if !hasImage(res) {
+		t.Fatal("expected the final board as an image")
+	}
+	if len(c.samples) != 0 {
+		t.Fatalf("expected the champion not to be asked on a full board, got %q", c.samples)
+	}
+	if h.currentGame(s) != nil {
+		t.Fatal("expected the finished game to be removed")
+	}
+}
+
+func TestSignedInUserResumesFromAnotherSession(t *testing.T) {
+	store := userdata.NewMemoryStore()
+	h := newHarness(t, WithUserGames(store))
+	first, c := newFakeSession("alice")
+	h.mustCall(first, "start_game", StartGameArgs{Name: "lunch"})
+	c.elicit(accept("B2", ""))
+	c.sample(reply("A1"))
+	h.mustCall(first, "take_turn", TakeTurnArgs{})
+
+	second, c := newFakeSession("alice")
+	second.id = "another-session"
+	c.elicit(accept("C3", ""))
+	c.sample(reply("A3"))
+	h.mustCall(second, "take_turn", TakeTurnArgs{GameID: "lunch"})
+
+	g := h.currentGame(userGameData{store: store, userID: "alice"})
+	if got := g.state.ToString(); got != "EAIG" {
+		t.Fatalf("expected the game to continue in the new session, got %q", got)
+	}
+	if len(first.data)+len(second.data) != 0 {
+		t.Fatal("expected nothing to be stored in the sessions")
+	}
+}
+
+func TestResignRemovesSignedInUsersGame(t *testing.T) {
+	store := userdata.NewMemoryStore()
+	h := newHarness(t, WithUserGames(store))
+	s, _ := newFakeSession("alice")
+	h.mustCall(s, "start_game", StartGameArgs{})
+
+	res := h.mustCall(s, "resign", ResignArgs{})
+	if !strings.Contains(resultText(res), "resigned") {
+		t.Fatalf("expected a resignation, got %q", resultText(res))
+	}
+	if g := h.currentGame(userGameData{store: store, userID: "alice"}); g != nil {
+		t.Fatalf("expected the resigned game to be removed, got %s", g.id)
+	}
+}
+
+func TestDailyPuzzleSolved(t *testing.T) {
+	games := archive.NewMemoryStore()
+	h := newHarness(t, WithGameArchive(games, "https://example.test"))
+	s, c := newFakeSession("alice")
+	h.mustCall(s, "daily_puzzle", DailyPuzzleArgs{})
+
+	// Play the solver's moves for both sides until the puzzle ends.
+	var text string
+	for turn := 0; turn < 5; turn++ {
+		g := h.currentGame(s)
+		if g == nil {
+			break
+		}
+		gs := g.state.Clone()
+		move, _ := gs.BestMove()
+		if err := gs.ApplyMove(move.Square); err != nil {
+			t.Fatal(err)
+		}
+		c.elicit(accept(gridOf(move.Square), ""))
+		if reply, ok := gs.BestMove(); ok {
+			c.sample(sampleReply{Text: gridOf(reply.Square), Model: "test-model"})
+		}
+		text = resultText(h.mustCall(s, "take_turn", TakeTurnArgs{}))
+	}
+
+	if !strings.Contains(text, "solved!") || !strings.Contains(text, "Current streak: 1 day") {
+		t.Fatalf("expected the puzzle to be solved, got %q", text)
+	}
+	if strings.Contains(text, "Share this game") {
+		t.Fatalf("expected puzzle attempts not to be archived, got %q", text)
+	}
+	stats, err := loadPuzzleStats(context.Background(), s)
+	if err != nil || stats.Solved != 1 || stats.Attempted != 1 {
+		t.Fatalf("unexpected puzzle stats %+v (%v)", stats, err)
+	}
+
+	res := h.mustCall(s, "daily_puzzle", DailyPuzzleArgs{})
+	if !strings.Contains(resultText(res), "already been played") {
+		t.Fatalf("expected the puzzle to be played once a day, got %q", resultText(res))
+	}
+}
+
+func TestResignedPuzzleFails(t *testing.T) {
+	h := newHarness(t)
+	s, _ := newFakeSession("alice")
+	h.mustCall(s, "daily_puzzle", DailyPuzzleArgs{})
+
+	res := h.mustCall(s, "resign", ResignArgs{})
+	if !strings.Contains(resultText(res), "failed") {
+		t.Fatalf("expected the puzzle to be failed, got %q", resultText(res))
+	}
+	stats, err := loadPuzzleStats(context.Background(), s)
+	if err != nil || stats.Solved != 0 || stats.Attempted != 1 {
+		t.Fatalf("unexpected puzzle stats %+v (%v)", stats, err)
+	}
+}
+
+func TestPuzzleStatsOutliveGames(t *testing.T) {
+	ctx := context.Background()
+	userGames, playerStats := userdata.NewMemoryStore(), userdata.NewMemoryStore()
+	h := newHarness(t, WithUserGames(userGames), WithPlayerStats(playerStats))
+	s, _ := newFakeSession("alice")
+	h.mustCall(s, "daily_puzzle", DailyPuzzleArgs{})
+	h.mustCall(s, "resign", ResignArgs{})
+
+	stats, err := loadPuzzleStats(ctx, userGameData{store: playerStats, userID: "alice"})
+	if err != nil || stats.Attempted != 1 {
+		t.Fatalf("expected the stats in the player stats store, got %+v (%v)", stats, err)
+	}
+	if _, found, _ := userGames.GetUserData(ctx, "alice", puzzleStatsKey); found {
+		t.Fatal("expected no stats next to the games, which expire")
+	}
+
+	// The games in progress expire, and the stats stay.
+	_ = userGames.DeleteUserData(ctx, "alice", registryKey)
+	res := h.mustCall(s, "daily_puzzle", DailyPuzzleArgs{})
+	if !strings.Contains(resultText(res), "already been played") {
+		t.Fatalf("expected the attempt to be remembered, got %q", resultText(res))
+	}
+}
+
+func TestTakeTurnEndsWhenUserWalksAway(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	h := newHarness(t, WithTurnDeadlines(20*time.Millisecond, time.Minute), WithMetrics(metrics.New(reg)))
+	s, c := newFakeSession("")
+	h.mustCall(s, "start_game", StartGameArgs{})
+
+	c.elicit(walkAway())
+	res := h.call(s, "take_turn", TakeTurnArgs{})
+	if res.IsError || !strings.Contains(resultText(res), "did not make a move within 20ms") {
+		t.Fatalf("expected the turn to end cleanly, got %q", resultText(res))
+	}
+	if len(c.samples) != 0 {
+		t.Fatalf("expected the champion not to be asked, got %q", c.samples)
+	}
+
+	c.elicit(accept("B2", ""))
+	c.sample(reply("A1"))
+	h.mustCall(s, "take_turn", TakeTurnArgs{})
+	if got := h.currentGame(s).state.ToString(); got != "EA" {
+		t.Fatalf("expected the next turn to play on, got %q", got)
+	}
+
+	expected := `
+# HELP tictacturing_turns_aborted_total Turns aborted after exhausting their retries or missing a deadline, by reason.
+# TYPE tictacturing_turns_aborted_total counter
+tictacturing_turns_aborted_total{reason="elicitation_timeout"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "tictacturing_turns_aborted_total"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTakeTurnEndsWhenChampionStalls(t *testing.T) {
+	h := newHarness(t, WithTurnDeadlines(time.Minute, 20*time.Millisecond))
+	s, c := newFakeSession("")
+	h.mustCall(s, "start_game", StartGameArgs{})
+
+	c.elicit(accept("B2", ""))
+	c.sample(stall())
+	res := h.call(s, "take_turn", TakeTurnArgs{})
+	if res.IsError || !strings.Contains(resultText(res), "champion did not reply within 20ms") {
+		t.Fatalf("expected the turn to end cleanly, got %q", resultText(res))
+	}
+	if got := h.currentGame(s).state.ToString(); got != "" {
+		t.Fatalf("expected the user's move not to be kept, got %q", got)
+	}
+}
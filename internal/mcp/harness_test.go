@@ -0,0 +1,291 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ggoodman/mcp-server-go/mcp"
+	"github.com/ggoodman/mcp-server-go/mcpservice"
+	"github.com/ggoodman/mcp-server-go/sessions"
+	"github.com/ggoodman/mcp-server-go/sessions/sampling"
+)
+
+// The harness drives the server returned by NewTickTackTuringServer
+// in-process, the way an MCP host would, with a fake session whose
+// elicitation answers and sampling replies are scripted by the test.
+
+// errScriptExhausted is returned by the fakes when a tool asks the client for
+// more than the test scripted.
+var errScriptExhausted = errors.New("fake client script exhausted")
+
+// elicitAnswer is one scripted reply to an elicitation. Content is decoded
+// into the elicitation target when Action is accept.
+type elicitAnswer struct {
+	Action  sessions.ElicitAction
+	Content map[string]any
+	Err     error
+	// Hang blocks until the request is cancelled, like a user who walked
+	// away.
+	Hang bool
+}
+
+// accept answers take_turn's move prompt.
+func accept(move, heckle string) elicitAnswer {
+	return elicitAnswer{Action: sessions.ElicitActionAccept, Content: map[string]any{"move": move, "heckle": heckle}}
+}
+
+func decline() elicitAnswer { return elicitAnswer{Action: sessions.ElicitActionDecline} }
+
+func cancel() elicitAnswer { return elicitAnswer{Action: sessions.ElicitActionCancel} }
+
+func walkAway() elicitAnswer { return elicitAnswer{Hang: true} }
+
+// sampleReply is one scripted reply to a sampling request.
+type sampleReply struct {
+	Text  string
+	Model string
+	Err   error
+	// Hang blocks until the request is cancelled.
+	Hang bool
+}
+
+// reply answers a sampling request with text from the default test model.
+func reply(text string) sampleReply { return sampleReply{Text: text, Model: "test-model"} }
+
+func stall() sampleReply { return sampleReply{Hang: true} }
+
+// fakeClient scripts the elicitation and sampling capabilities of a session
+// and records what the server asked for.
+type fakeClient struct {
+	mu      sync.Mutex
+	answers []elicitAnswer
+	replies []sampleReply
+	// prompts and samples record the text of every elicitation and the user
+	// message of every sampling request, in order.
+	prompts []string
+	samples []string
+}
+
+// elicit queues answers for the next elicitations.
+func (c *fakeClient) elicit(answers ...elicitAnswer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.answers = append(c.answers, answers...)
+}
+
+// sample queues replies for the next sampling requests.
+func (c *fakeClient) sample(replies ...sampleReply) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.replies = append(c.replies, replies...)
+}
+
+// pending reports how many scripted answers and replies are still unused.
+func (c *fakeClient) pending() (answers, replies int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.answers), len(c.replies)
+}
+
+type fakeElicitation struct{ c *fakeClient }
+
+func (e fakeElicitation) Elicit(ctx context.Context, text string, subject any, opts ...sessions.ElicitOption) (sessions.ElicitAction, error) {
+	e.c.mu.Lock()
+	defer e.c.mu.Unlock()
+	e.c.prompts = append(e.c.prompts, text)
+	if len(e.c.answers) == 0 {
+		return sessions.ElicitActionCancel, errScriptExhausted
+	}
+	a := e.c.answers[0]
+	e.c.answers = e.c.answers[1:]
+	if a.Hang {
+		e.c.mu.Unlock()
+		<-ctx.Done()
+		e.c.mu.Lock()
+		return sessions.ElicitActionCancel, ctx.Err()
+	}
+	if a.Err != nil {
+		return sessions.ElicitActionCancel, a.Err
+	}
+	if a.Action == sessions.ElicitActionAccept {
+		b, err := json.Marshal(a.Content)
+		if err != nil {
+			return sessions.ElicitActionCancel, err
+		}
+		if err := json.Unmarshal(b, subject); err != nil {
+			return sessions.ElicitActionCancel, err
+		}
+	}
+	return a.Action, nil
+}
+
+type fakeSampling struct{ c *fakeClient }
+
+func (f fakeSampling) CreateMessage(ctx context.Context, system string, user sampling.Message, opts ...sampling.Option) (*sessions.SampleResult, error) {
+	f.c.mu.Lock()
+	defer f.c.mu.Unlock()
+	f.c.samples = append(f.c.samples, user.Content.AsContentBlock().Text)
+	if len(f.c.replies) == 0 {
+		return nil, errScriptExhausted
+	}
+	r := f.c.replies[0]
+	f.c.replies = f.c.replies[1:]
+	if r.Hang {
+		f.c.mu.Unlock()
+		<-ctx.Done()
+		f.c.mu.Lock()
+		return nil, ctx.Err()
+	}
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	return &sessions.SampleResult{
+		Message:    sampling.AssistantText(r.Text),
+		Model:      r.Model,
+		StopReason: "endTurn",
+	}, nil
+}
+
+// fakeSession is an in-memory sessions.Session. A nil client means the host
+// supports neither elicitation nor sampling.
+type fakeSession struct {
+	id     string
+	userID string
+	client *fakeClient
+
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func newFakeSession(userID string) (*fakeSession, *fakeClient) {
+	c := &fakeClient{}
+	return &fakeSession{id: "session-" + userID, userID: userID, client: c, data: map[string][]byte{}}, c
+}
+
+func (s *fakeSession) SessionID() string       { return s.id }
+func (s *fakeSession) UserID() string          { return s.userID }
+func (s *fakeSession) ProtocolVersion() string { return "2025-06-18" }
+
+func (s *fakeSession) GetSamplingCapability() (sessions.SamplingCapability, bool) {
+	if s.client == nil {
+		return nil, false
+	}
+	return fakeSampling{s.client}, true
+}
+
+func (s *fakeSession) GetRootsCapability() (sessions.RootsCapability, bool) { return nil, false }
+
+func (s *fakeSession) GetElicitationCapability() (sessions.ElicitationCapability, bool) {
+	if s.client == nil {
+		return nil, false
+	}
+	return fakeElicitation{s.client}, true
+}
+
+func (s *fakeSession) PutData(ctx context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = append([]byte(nil), value...)
+	return nil
+}
+
+func (s *fakeSession) GetData(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.data[key]
+	return v, ok, nil
+}
+
+func (s *fakeSession) DeleteData(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+	return nil
+}
+
+// harness is a server under test.
+type harness struct {
+	t     *testing.T
+	srv   *ticTacTuring
+	tools mcpservice.ToolsCapability
+	// ctx is the context of tool calls, e.g. one carrying a client IP.
+	ctx context.Context
+}
+
+func newHarness(t *testing.T, opts ...ServerOption) *harness {
+	t.Helper()
+	s, _ := newFakeSession("")
+	srv := newTicTacTuring(opts...)
+	tools, ok, err := srv.server().GetToolsCapability(context.Background(), s)
+	if err != nil || !ok {
+		t.Fatalf("expected a tools capability, got ok=%v err=%v", ok, err)
+	}
+	return &harness{t: t, srv: srv, tools: tools, ctx: context.Background()}
+}
+
+// call invokes a tool with args, which are encoded as JSON.
+func (h *harness) call(s sessions.Session, name string, args any) *mcp.CallToolResult {
+	h.t.Helper()
+	b, err := json.Marshal(args)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	res, err := h.tools.CallTool(h.ctx, s, &mcp.CallToolRequestReceived{Name: name, Arguments: b})
+	if err != nil {
+		h.t.Fatalf("%s: %v", name, err)
+	}
+	return res
+}
+
+// mustCall invokes a tool and fails the test if it reports an error.
+func (h *harness) mustCall(s sessions.Session, name string, args any) *mcp.CallToolResult {
+	h.t.Helper()
+	res := h.call(s, name, args)
+	if res.IsError {
+		h.t.Fatalf("%s failed: %s", name, resultText(res))
+	}
+	return res
+}
+
+// currentGame loads the current game of the session straight from storage.
+func (h *harness) currentGame(d gameData) *activeGame {
+	h.t.Helper()
+	reg, err := loadRegistry(context.Background(), d)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	if reg.Current == "" {
+		return nil
+	}
+	g, found, err := readGame(context.Background(), d, reg.Current)
+	if err != nil || !found {
+		h.t.Fatalf("expected game %s, got found=%v err=%v", reg.Current, found, err)
+	}
+	return g
+}
+
+// resultText joins the text blocks of a tool result.
+func resultText(res *mcp.CallToolResult) string {
+	var parts []string
+	for _, b := range res.Content {
+		if b.Type == mcp.ContentTypeText {
+			parts = append(parts, b.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// hasImage reports whether a tool result carries an image block, such as the
+// rendered board.
+func hasImage(res *mcp.CallToolResult) bool {
+	for _, b := range res.Content {
+		if b.Type == mcp.ContentTypeImage {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/ggoodman/mcp-server-go/mcpservice"
+	"github.com/ggoodman/mcp-server-go/sessions"
+	"github.com/ggoodman/tic-tac-turing/internal/archive"
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+)
+
+type ImportGameArgs struct {
+	Record string `json:"record" jsonschema:"required,description=A game in the portable record format, as downloaded from a game page"`
+}
+
+// importGame archives a game exported from this or another server and
+// shares its page. The game belongs to the session's user whatever its
+// record says, and is not rated.
+func (t *ticTacTuring) importGame(ctx context.Context, s sessions.Session, w mcpservice.ToolResponseWriter, r *mcpservice.ToolRequest[ImportGameArgs]) error {
+	if t.games == nil {
+		w.SetError(true)
+		w.AppendText("Game archiving is not enabled on this server.")
+		return nil
+	}
+	if !t.allowCall(ctx, s, w, "import_game", t.limits.startGameUser, t.limits.startGameIP) {
+		return nil
+	}
+
+	rec, err := ticktacktoe.ParseRecord(r.Args().Record)
+	if err != nil {
+		w.SetError(true)
+		w.AppendText("The record could not be read: " + err.Error())
+		return nil
+	}
+	g, err := archive.GameFromRecord(rec)
+	if err != nil {
+		w.SetError(true)
+		w.AppendText("The record could not be imported: " + err.Error())
+		return nil
+	}
+	g.UserID = s.UserID()
+	if g.FinishedAt.IsZero() {
+		g.FinishedAt = time.Now().UTC()
+	}
+	if review, err := archive.NewReview(g); err == nil && len(g.Moves) > 0 {
+		g.Review = review
+	}
+
+	if err := t.games.PutGame(ctx, g); err != nil {
+		w.SetError(true)
+		_ = w.AppendText("Failed to store the game")
+		return nil
+	}
+	_ = w.AppendText("The game was imported. Share it: " + t.publicUrl + "/games/" + g.ID)
+	return nil
+}
@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ggoodman/tic-tac-turing/internal/archive"
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+)
+
+func TestImportGame(t *testing.T) {
+	ctx := context.Background()
+	games := archive.NewMemoryStore()
+	h := newHarness(t, WithGameArchive(games, "https://example.test"))
+	s, _ := newFakeSession("alice")
+
+	record := "" +
+		"[Date \"2025.10.05\"]\n" +
+		"[X \"mallory\"]\n" +
+		"[Result \"1-0\"]\n" +
+		"\n" +
+		"1. A1 {one} A2\n" +
+		"2. B1 B2\n" +
+		"3. C1 {three}\n" +
+		"1-0\n"
+	text := resultText(h.mustCall(s, "import_game", ImportGameArgs{Record: record}))
+
+	m := shareLink.FindStringSubmatch(text)
+	if m == nil {
+		t.Fatalf("expected a share link, got %q", text)
+	}
+	g, found, err := games.GetGame(ctx, m[1])
+	if err != nil || !found {
+		t.Fatalf("expected imported game %s, got found=%v err=%v", m[1], found, err)
+	}
+	if g.UserID != "alice" {
+		t.Fatalf("expected the game to belong to the importing user, not to the record's X tag, got %q", g.UserID)
+	}
+	if g.Moves != "ADBEC" || g.LastHeckle() != "three" || g.Review == nil {
+		t.Fatalf("unexpected imported game %+v", g)
+	}
+	if gs, _ := g.State(); g.Outcome(gs) != ticktacktoe.ResultXWins {
+		t.Fatalf("expected the imported game to be won by X, got %q", g.Outcome(gs))
+	}
+
+	res := h.call(s, "import_game", ImportGameArgs{Record: "1. A1 A1 *"})
+	if !res.IsError || !strings.Contains(resultText(res), "could not be read") {
+		t.Fatalf("expected an illegal record to be refused, got %q", resultText(res))
+	}
+}
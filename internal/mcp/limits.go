@@ -0,0 +1,151 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/ggoodman/mcp-server-go/mcpservice"
+	"github.com/ggoodman/mcp-server-go/sessions"
+	"github.com/ggoodman/tic-tac-turing/internal/ratelimit"
+)
+
+// maxHeckleLength caps heckles, in characters. Longer heckles are cut short
+// before they are stored or reach the champion.
+const maxHeckleLength = 280
+
+// sampleMaxTokens caps every sampling reply. The champion only ever needs to
+// name a square or answer ACCEPT or DECLINE, so together with the sampling
+// budget this bounds what a player can spend of the host's model.
+const sampleMaxTokens = 32
+
+// Limits bounds how much a single user or client address may ask of the
+// server. Zero rates are unlimited.
+type Limits struct {
+	// StartGamePerUser and StartGamePerIP limit calls to start_game,
+	// daily_puzzle and import_game.
+	StartGamePerUser ratelimit.Rate
+	StartGamePerIP   ratelimit.Rate
+	// TakeTurnPerUser and TakeTurnPerIP limit calls to take_turn.
+	TakeTurnPerUser ratelimit.Rate
+	TakeTurnPerIP   ratelimit.Rate
+	// SamplingBudget limits the sampling requests made on behalf of each
+	// user, each capped at sampleMaxTokens tokens.
+	SamplingBudget ratelimit.Rate
+}
+
+// limiters enforces Limits; nil limiters allow everything.
+type limiters struct {
+	startGameUser, startGameIP *ratelimit.Limiter
+	takeTurnUser, takeTurnIP   *ratelimit.Limiter
+	sampling                   *ratelimit.Limiter
+}
+
+// WithLimits rate-limits starting games, playing turns and sampling.
+func WithLimits(l Limits) ServerOption {
+	return func(t *ticTacTuring) {
+		t.limits = limiters{
+			startGameUser: ratelimit.New(l.StartGamePerUser),
+			startGameIP:   ratelimit.New(l.StartGamePerIP),
+			takeTurnUser:  ratelimit.New(l.TakeTurnPerUser),
+			takeTurnIP:    ratelimit.New(l.TakeTurnPerIP),
+			sampling:      ratelimit.New(l.SamplingBudget),
+		}
+	}
+}
+
+// WithClientIPHeader takes the client address of HTTP requests from header,
+// such as Fly-Client-IP, instead of the connection's remote address. Only
+// use it behind a proxy that sets the header.
+func WithClientIPHeader(header string) ServerOption {
+	return func(t *ticTacTuring) { t.clientIPHeader = header }
+}
+
+// limitKey identifies the player for per-user limits: the signed-in user,
+// or the session when there is none.
+func limitKey(s sessions.Session) string {
+	if id := s.UserID(); id != "" {
+		return "user:" + id
+	}
+	return "session:" + s.SessionID()
+}
+
+// allowCall counts a call to tool against perUser and perIP. When either
+// limit is exhausted it counts against neither, writes an error result and
+// returns false.
+func (t *ticTacTuring) allowCall(ctx context.Context, s sessions.Session, w mcpservice.ToolResponseWriter, tool string, perUser, perIP *ratelimit.Limiter) bool {
+	limit, scope := perUser.Rate(), "user"
+	reserved, allowed := perUser.Reserve(limitKey(s))
+	if ip := clientIP(ctx); allowed && ip != "" {
+		limit, scope = perIP.Rate(), "ip"
+		if allowed = perIP.Allow(ip); !allowed {
+			// A refused call must not cost the user anything.
+			reserved.Cancel()
+		}
+	}
+	if allowed {
+		return true
+	}
+	t.metrics.RateLimited(tool, scope)
+	w.SetError(true)
+	_ = w.AppendText(fmt.Sprintf("Slow down! %s may be called at most %d times every %s. Tell the user to try again in a little while; do not retry right away.", tool, limit.N, limit.Per))
+	return false
+}
+
+// hasSamplingBudget reports whether the player may still have the champion
+// sampled. When not it writes an error result.
+func (t *ticTacTuring) hasSamplingBudget(s sessions.Session, w mcpservice.ToolResponseWriter) bool {
+	if t.limits.sampling.Tokens(limitKey(s)) >= 1 {
+		return true
+	}
+	t.metrics.RateLimited("sampling", "budget")
+	w.SetError(true)
+	_ = w.AppendText(samplingBudgetMessage)
+	return false
+}
+
+const samplingBudgetMessage = "The champion has played all it will play with this user for now. Tell the user to come back later."
+
+// truncateHeckle cuts heckle down to maxHeckleLength characters.
+func truncateHeckle(heckle string) string {
+	if r := []rune(heckle); len(r) > maxHeckleLength {
+		return string(r[:maxHeckleLength])
+	}
+	return heckle
+}
+
+type clientIPKey struct{}
+
+// withClientIP returns a copy of ctx carrying the client address ip.
+func withClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// clientIP returns the client address of the request being served, or ""
+// outside HTTP requests.
+func clientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
+
+// withClientIPFrom records the client address of each request for per-IP
+// limits: the first address in header when set, the remote address
+// otherwise.
+func withClientIPFrom(next http.Handler, header string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := ""
+		if header != "" {
+			ip, _, _ = strings.Cut(r.Header.Get(header), ",")
+			ip = strings.TrimSpace(ip)
+		}
+		if ip == "" {
+			ip, _, _ = net.SplitHostPort(r.RemoteAddr)
+		}
+		if ip != "" {
+			r = r.WithContext(withClientIP(r.Context(), ip))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
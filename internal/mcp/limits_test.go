@@ -0,0 +1,141 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ggoodman/tic-tac-turing/internal/ratelimit"
+)
+
+func TestStartGameIsRateLimitedPerUser(t *testing.T) {
+	h := newHarness(t, WithLimits(Limits{StartGamePerUser: ratelimit.Rate{N: 2, Per: time.Hour}}))
+	alice, _ := newFakeSession("alice")
+
+	h.mustCall(alice, "start_game", StartGameArgs{})
+	h.mustCall(alice, "start_game", StartGameArgs{})
+	res := h.call(alice, "start_game", StartGameArgs{})
+	if !res.IsError || !strings.Contains(resultText(res), "at most 2 times every 1h0m0s") {
+		t.Fatalf("expected the third game to be refused, got %q", resultText(res))
+	}
+	// Puzzles count as starting a game.
+	if res := h.call(alice, "daily_puzzle", DailyPuzzleArgs{}); !res.IsError || !strings.Contains(resultText(res), "Slow down") {
+		t.Fatalf("expected the puzzle to be refused, got %q", resultText(res))
+	}
+
+	bob, _ := newFakeSession("bob")
+	h.mustCall(bob, "start_game", StartGameArgs{})
+}
+
+func TestTakeTurnIsRateLimitedPerIP(t *testing.T) {
+	h := newHarness(t, WithLimits(Limits{TakeTurnPerIP: ratelimit.Rate{N: 1, Per: time.Minute}}))
+	h.ctx = withClientIP(context.Background(), "203.0.113.7")
+
+	alice, c := newFakeSession("alice")
+	h.mustCall(alice, "start_game", StartGameArgs{})
+	c.elicit(accept("B2", ""))
+	c.sample(reply("A1"))
+	h.mustCall(alice, "take_turn", TakeTurnArgs{})
+
+	// Another user behind the same address shares its limit.
+	bob, _ := newFakeSession("bob")
+	h.mustCall(bob, "start_game", StartGameArgs{})
+	if res := h.call(bob, "take_turn", TakeTurnArgs{}); !res.IsError || !strings.Contains(resultText(res), "Slow down") {
+		t.Fatalf("expected the turn to be refused, got %q", resultText(res))
+	}
+
+	h.ctx = withClientIP(context.Background(), "198.51.100.1")
+	if res := h.call(bob, "take_turn", TakeTurnArgs{}); strings.Contains(resultText(res), "Slow down") {
+		t.Fatalf("expected another address to have its own limit, got %q", resultText(res))
+	}
+}
+
+func TestIPRejectionKeepsUserBudget(t *testing.T) {
+	h := newHarness(t, WithLimits(Limits{
+		StartGamePerUser: ratelimit.Rate{N: 1, Per: time.Hour},
+		StartGamePerIP:   ratelimit.Rate{N: 1, Per: time.Hour},
+	}))
+	bob, _ := newFakeSession("bob")
+	h.ctx = withClientIP(context.Background(), "203.0.113.7")
+	h.mustCall(bob, "start_game", StartGameArgs{})
+
+	// Alice is refused by the limit of the address she shares with bob...
+	alice, _ := newFakeSession("alice")
+	if res := h.call(alice, "start_game", StartGameArgs{}); !res.IsError || !strings.Contains(resultText(res), "Slow down") {
+		t.Fatalf("expected the game to be refused, got %q", resultText(res))
+	}
+
+	// ...which does not use up her own limit.
+	h.ctx = withClientIP(context.Background(), "198.51.100.1")
+	h.mustCall(alice, "start_game", StartGameArgs{})
+}
+
+func TestHeckleIsTruncated(t *testing.T) {
+	h := newHarness(t)
+	s, c := newFakeSession("")
+	h.mustCall(s, "start_game", StartGameArgs{})
+
+	c.elicit(accept("B2", strings.Repeat("blah ", 1000)))
+	c.sample(reply("A1"))
+	h.mustCall(s, "take_turn", TakeTurnArgs{})
+
+	heckle := h.currentGame(s).heckles[0]
+	if len(heckle) != maxHeckleLength {
+		t.Fatalf("expected the heckle to be cut to %d characters, got %d", maxHeckleLength, len(heckle))
+	}
+	if !strings.HasSuffix(c.samples[0], "User heckle: "+heckle) {
+		t.Fatal("expected the champion to receive the truncated heckle")
+	}
+}
+
+func TestSamplingBudget(t *testing.T) {
+	h := newHarness(t, WithLimits(Limits{SamplingBudget: ratelimit.Rate{N: 2, Per: 24 * time.Hour}}))
+	s, c := newFakeSession("alice")
+	h.mustCall(s, "start_game", StartGameArgs{})
+
+	c.elicit(accept("B2", ""))
+	c.sample(reply("A1"))
+	h.mustCall(s, "take_turn", TakeTurnArgs{})
+
+	// The second request spends the budget on an illegal move and the third
+	// is never sent.
+	c.elicit(accept("C1", ""))
+	c.sample(reply("B2"), reply("A3"))
+	res := h.call(s, "take_turn", TakeTurnArgs{})
+	if !res.IsError || !strings.Contains(resultText(res), "played all it will play") {
+		t.Fatalf("expected the turn to run out of budget, got %q", resultText(res))
+	}
+	if len(c.samples) != 2 {
+		t.Fatalf("expected 2 sampling requests, got %d", len(c.samples))
+	}
+
+	if res := h.call(s, "take_turn", TakeTurnArgs{}); !res.IsError || !strings.Contains(resultText(res), "played all it will play") {
+		t.Fatalf("expected the next turn to be refused up front, got %q", resultText(res))
+	}
+	if res := h.call(s, "offer_draw", OfferDrawArgs{}); !res.IsError || !strings.Contains(resultText(res), "played all it will play") {
+		t.Fatalf("expected the draw offer to be refused, got %q", resultText(res))
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	var got string
+	h := withClientIPFrom(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = clientIP(r.Context())
+	}), "Fly-Client-IP")
+
+	r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if got != "10.0.0.1" {
+		t.Fatalf("expected the remote address without a header, got %q", got)
+	}
+
+	r.Header.Set("Fly-Client-IP", "203.0.113.7, 10.0.0.1")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if got != "203.0.113.7" {
+		t.Fatalf("expected the address from the header, got %q", got)
+	}
+}
@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ggoodman/tic-tac-turing/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	h := newHarness(t, WithMetrics(metrics.New(reg)))
+	s, c := newFakeSession("")
+	h.mustCall(s, "start_game", StartGameArgs{})
+
+	// One declined elicitation and two unusable champion replies.
+	c.elicit(decline(), accept("B2", ""))
+	c.sample(reply("B2"), reply("I'll take the corner"), reply("C3"))
+	h.mustCall(s, "take_turn", TakeTurnArgs{})
+
+	// A confused champion aborts the turn.
+	c.elicit(accept("A1", ""))
+	c.sample(reply("B2"), sampleReply{Err: errors.New("host timed out")}, reply(""))
+	h.call(s, "take_turn", TakeTurnArgs{})
+
+	h.mustCall(s, "resign", ResignArgs{})
+
+	expected := `
+# HELP tictacturing_elicitation_declines_total Move elicitations the user declined or cancelled, by action.
+# TYPE tictacturing_elicitation_declines_total counter
+tictacturing_elicitation_declines_total{action="decline"} 1
+# HELP tictacturing_games_finished_total Games finished, by outcome and by how they ended.
+# TYPE tictacturing_games_finished_total counter
+tictacturing_games_finished_total{outcome="champion_win",termination="resignation"} 1
+# HELP tictacturing_games_started_total Games started, by kind (standard, custom or puzzle).
+# TYPE tictacturing_games_started_total counter
+tictacturing_games_started_total{kind="standard"} 1
+# HELP tictacturing_invalid_model_moves_total Champion replies that were not a legal move.
+# TYPE tictacturing_invalid_model_moves_total counter
+tictacturing_invalid_model_moves_total 4
+# HELP tictacturing_sampling_failures_total Sampling requests that returned an error, by purpose.
+# TYPE tictacturing_sampling_failures_total counter
+tictacturing_sampling_failures_total{purpose="move"} 1
+# HELP tictacturing_turns_aborted_total Turns aborted after exhausting their retries or missing a deadline, by reason.
+# TYPE tictacturing_turns_aborted_total counter
+tictacturing_turns_aborted_total{reason="champion_confused"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"tictacturing_elicitation_declines_total",
+		"tictacturing_games_finished_total",
+		"tictacturing_games_started_total",
+		"tictacturing_invalid_model_moves_total",
+		"tictacturing_sampling_failures_total",
+		"tictacturing_turns_aborted_total",
+	); err != nil {
+		t.Fatal(err)
+	}
+	if n := testutil.CollectAndCount(reg, "tictacturing_sampling_duration_seconds"); n != 1 {
+		t.Fatalf("expected one sampling latency series, got %d", n)
+	}
+}
@@ -0,0 +1,197 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ggoodman/mcp-server-go/mcpservice"
+	"github.com/ggoodman/mcp-server-go/sessions"
+	"github.com/ggoodman/tic-tac-turing/internal/metrics"
+	"github.com/ggoodman/tic-tac-turing/internal/puzzle"
+	"github.com/ggoodman/tic-tac-turing/internal/userdata"
+)
+
+// puzzleStatsKey holds the player's JSON puzzle.Stats; see statsData.
+const puzzleStatsKey = "tick_tack_turing_puzzle_stats"
+
+type DailyPuzzleArgs struct{}
+
+// WithPlayerStats keeps the puzzle stats of authenticated users in store,
+// which unlike the store of games in progress should never expire: a streak
+// is history worth keeping even after a long break.
+func WithPlayerStats(store userdata.Store) ServerOption {
+	return func(t *ticTacTuring) { t.playerStats = store }
+}
+
+// statsData returns where the puzzle stats of the player are kept: in the
+// player stats store for signed-in users when there is one, and next to
+// their games, in games, otherwise.
+func (t *ticTacTuring) statsData(userID string, games gameData) gameData {
+	if t.playerStats != nil && userID != "" {
+		return tracedData{d: userGameData{store: t.playerStats, userID: userID}, backend: "stats"}
+	}
+	return games
+}
+
+func loadPuzzleStats(ctx context.Context, d gameData) (puzzle.Stats, error) {
+	var stats puzzle.Stats
+	b, found, err := d.GetData(ctx, puzzleStatsKey)
+	if err != nil {
+		return stats, fmt.Errorf("error loading puzzle stats: %w", err)
+	}
+	if !found {
+		return stats, nil
+	}
+	if err := json.Unmarshal(b, &stats); err != nil {
+		return stats, fmt.Errorf("error decoding puzzle stats: %w", err)
+	}
+	return stats, nil
+}
+
+func storePuzzleStats(ctx context.Context, d gameData, stats puzzle.Stats) error {
+	b, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	return d.PutData(ctx, puzzleStatsKey, b)
+}
+
+// dailyPuzzle starts today's puzzle as a new game. Each puzzle may be
+// attempted once.
+func (t *ticTacTuring) dailyPuzzle(ctx context.Context, s sessions.Session, w mcpservice.ToolResponseWriter, r *mcpservice.ToolRequest[DailyPuzzleArgs]) error {
+	_, ok := s.GetElicitationCapability()
+	if !ok {
+		w.SetError(true)
+		w.AppendText("To challenge the champion, you need a more powerful client that can support elicitation.")
+		return nil
+	}
+
+	_, ok = s.GetSamplingCapability()
+	if !ok {
+		w.SetError(true)
+		w.AppendText("To challenge the champion, you need a more powerful client that can support sampling.")
+		return nil
+	}
+
+	if !t.allowCall(ctx, s, w, "daily_puzzle", t.limits.startGameUser, t.limits.startGameIP) {
+		return nil
+	}
+
+	d := t.gameData(s)
+	stats, err := loadPuzzleStats(ctx, t.statsData(s.UserID(), d))
+	if err != nil {
+		w.SetError(true)
+		_ = w.AppendText("Error starting puzzle")
+		return nil
+	}
+	reg, err := loadRegistry(ctx, d)
+	if err != nil {
+		w.SetError(true)
+		_ = w.AppendText("Error starting puzzle")
+		return nil
+	}
+
+	p := puzzle.ForDate(time.Now())
+	w.SetMeta("puzzleDate", p.Date)
+
+	if stats.Played(p.Date) {
+		w.AppendText(fmt.Sprintf("Today's puzzle (%s) has already been played. Come back tomorrow for a new one!\n\n%s", p.Date, puzzleStatsText(stats)))
+		return nil
+	}
+	for _, g := range readGames(ctx, d, reg) {
+		if g.puzzle != nil && g.puzzle.Date == p.Date {
+			w.SetError(true)
+			w.AppendText(fmt.Sprintf("Today's puzzle is already in progress as game %s. Call switch_game with that game_id to continue it.", g.label()))
+			return nil
+		}
+	}
+	if len(reg.IDs) >= maxGames {
+		w.SetError(true)
+		w.AppendText(fmt.Sprintf("There are already %d games in progress. Finish or resign one of them first; call list_games to see them.", len(reg.IDs)))
+		return nil
+	}
+
+	gs, err := p.State()
+	if err != nil {
+		w.SetError(true)
+		_ = w.AppendText("Error starting puzzle")
+		return nil
+	}
+	game := &activeGame{
+		id:     newActiveGameID(),
+		name:   "puzzle " + p.Date,
+		state:  gs,
+		puzzle: &p,
+	}
+	if err := addGame(ctx, d, game); err != nil {
+		w.SetError(true)
+		_ = w.AppendText("Error starting puzzle")
+		return nil
+	}
+	w.SetMeta("gameId", game.id)
+	t.metrics.GameStarted(metrics.KindPuzzle)
+
+	w.AppendText(p.String() + " Takebacks are not allowed.")
+	w.AppendText(fmt.Sprintf("Puzzle game %s is now the current game. The user is X. You MUST present the following game board to the user exactly as shown, with no alterations, together with the goal above. Then immediately call the `take_turn` tool.", game.label()))
+	w.AppendText("# Game state\n```text\n" + gs.BoardString() + "\n```")
+	appendBoardImage(w, gs)
+	return nil
+}
+
+// puzzleMovesExhausted reports whether the human has used up the moves of a
+// puzzle attempt without winning.
+func puzzleMovesExhausted(g *activeGame) bool {
+	return g.puzzle != nil && g.state.PlayerToMove() != 0 && g.puzzle.MovesMade(g.state) >= g.puzzle.Within
+}
+
+// finishPuzzle records the outcome of a finished puzzle attempt and reports
+// the player's puzzle stats. result is "" when the game ended on the board.
+func (t *ticTacTuring) finishPuzzle(ctx context.Context, userID string, w mcpservice.ToolResponseWriter, g *activeGame, result string) {
+	if result == "" {
+		result = g.state.Result()
+	}
+	solved := g.puzzle.Solved(g.state, result)
+
+	if solved {
+		_ = w.AppendText(fmt.Sprintf("Puzzle %s solved!", g.puzzle.Date))
+	} else {
+		_ = w.AppendText(fmt.Sprintf("Puzzle %s failed. Better luck tomorrow!", g.puzzle.Date))
+	}
+
+	d := t.statsData(userID, g.data)
+	stats, err := loadPuzzleStats(ctx, d)
+	if err != nil {
+		return
+	}
+	stats.Record(g.puzzle.Date, solved)
+	if err := storePuzzleStats(ctx, d, stats); err != nil {
+		return
+	}
+	w.SetMeta("puzzleStreak", stats.Streak)
+	_ = w.AppendText(puzzleStatsText(stats))
+}
+
+func puzzleStatsText(stats puzzle.Stats) string {
+	var b strings.Builder
+	b.WriteString("## Puzzle stats\n\n")
+	fmt.Fprintf(&b, "- Solved: %d of %d\n", stats.Solved, stats.Attempted)
+	fmt.Fprintf(&b, "- Current streak: %s (best: %d)\n", pluralize(stats.Streak, "day", "days"), stats.BestStreak)
+	if n := len(stats.History); n > 0 {
+		b.WriteString("- Recent: ")
+		for i, r := range stats.History[max(0, n-7):] {
+			if i > 0 {
+				b.WriteString(" ")
+			}
+			if r.Solved {
+				b.WriteString("✓")
+			} else {
+				b.WriteString("✗")
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ggoodman/mcp-server-go/mcpservice"
+	"github.com/ggoodman/mcp-server-go/sessions"
+	"github.com/ggoodman/tic-tac-turing/internal/archive"
+	"github.com/ggoodman/tic-tac-turing/internal/rating"
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+)
+
+type RatingsArgs struct{}
+
+// leaderboardSize is how many players of each kind the ratings tool lists.
+const leaderboardSize = 10
+
+// rateGame applies a finished game to the ratings of the signed-in user and
+// the champion. Abandoned games count as lost by the user. Other unfinished
+// games, games from a custom starting position and anonymous users are not
+// rated. Like archiving, rating is best-effort.
+func (t *ticTacTuring) rateGame(ctx context.Context, userID string, w mcpservice.ToolResponseWriter, g *archive.Game) {
+	if t.ratings == nil || userID == "" || g.SetUpPlies > 0 {
+		return
+	}
+	gs, err := g.State()
+	if err != nil {
+		return
+	}
+
+	var score float64
+	switch outcome := g.Outcome(gs); {
+	case g.Termination == archive.TerminationAbandoned:
+		// Walking away from a game is losing it, or a player about to lose
+		// could keep their rating by never coming back.
+		score = rating.ScoreLoss
+	case outcome == ticktacktoe.ResultXWins:
+		score = rating.ScoreWin
+	case outcome == ticktacktoe.ResultOWins:
+		score = rating.ScoreLoss
+	case outcome == ticktacktoe.ResultDraw:
+		score = rating.ScoreDraw
+	default:
+		return
+	}
+
+	before, err := t.ratings.GetRating(ctx, rating.KindUser, userID)
+	if err != nil {
+		return
+	}
+	user, champion, err := t.ratings.RecordGame(ctx, userID, rating.ChampionID("", g.Model), score)
+	if err != nil {
+		return
+	}
+
+	w.SetMeta("rating", user.Rating)
+	_ = w.AppendText(fmt.Sprintf("The user's rating is now %.0f (%+.0f). The champion (%s) is rated %.0f.", user.Rating, user.Rating-before.Rating, champion.Player, champion.Rating))
+}
+
+// showRatings describes the user's rating and the leaderboards.
+func (t *ticTacTuring) showRatings(ctx context.Context, s sessions.Session, w mcpservice.ToolResponseWriter, r *mcpservice.ToolRequest[RatingsArgs]) error {
+	if t.ratings == nil {
+		w.SetError(true)
+		w.AppendText("Ratings are not enabled on this server.")
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteString("# Ratings\n\n")
+
+	if userID := s.UserID(); userID != "" {
+		me, err := t.ratings.GetRating(ctx, rating.KindUser, userID)
+		if err != nil {
+			w.SetError(true)
+			_ = w.AppendText("Failed to load ratings")
+			return nil
+		}
+		if me.Games == 0 {
+			fmt.Fprintf(&b, "The user is unrated. Finish a game against the champion to get a rating (everyone starts at %.0f).\n\n", rating.Initial)
+		} else {
+			fmt.Fprintf(&b, "The user's rating is %.0f after %s (%d won, %d drawn, %d lost).\n\n", me.Rating, pluralize(me.Games, "game", "games"), me.Wins, me.Draws, me.Losses)
+		}
+		w.SetMeta("rating", me.Rating)
+	} else {
+		b.WriteString("The user is not signed in, so their games are not rated.\n\n")
+	}
+
+	for _, board := range []struct {
+		kind  rating.Kind
+		title string
+	}{
+		{rating.KindUser, "Top players"},
+		{rating.KindChampion, "Top champions"},
+	} {
+		top, err := t.ratings.Leaderboard(ctx, board.kind, leaderboardSize)
+		if err != nil {
+			w.SetError(true)
+			_ = w.AppendText("Failed to load ratings")
+			return nil
+		}
+		fmt.Fprintf(&b, "## %s\n\n", board.title)
+		if len(top) == 0 {
+			b.WriteString("Nobody is rated yet.\n\n")
+			continue
+		}
+		b.WriteString("| # | Player | Rating | Games |\n|---|---|---|---|\n")
+		for i, r := range top {
+			name := r.Player
+			if board.kind == rating.KindUser {
+				name = t.names.Name(r.Player)
+			}
+			fmt.Fprintf(&b, "| %d | %s | %.0f | %d |\n", i+1, strings.ReplaceAll(name, "|", `\|`), r.Rating, r.Games)
+		}
+		b.WriteString("\n")
+	}
+
+	if t.publicUrl != "" {
+		fmt.Fprintf(&b, "Full ratings: %s/ratings\n", t.publicUrl)
+	}
+
+	w.AppendText(b.String())
+	return nil
+}
@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/ggoodman/mcp-server-go/mcpservice"
+	"github.com/ggoodman/mcp-server-go/sessions"
+	"github.com/ggoodman/mcp-server-go/sessions/sampling"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer returns a tracer of the current global tracer provider, which is a
+// no-op until the program installs one.
+func tracer() trace.Tracer {
+	return otel.Tracer("github.com/ggoodman/tic-tac-turing/internal/mcp")
+}
+
+// endSpan ends span, marking it failed when err is non-nil.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// newTool is mcpservice.NewTool with every invocation wrapped in a span
+// named after the tool. The session passed to fn records its own spans for
+// elicitation, sampling and data access, so a slow turn shows whether the
+// user, the host's model or the storage was waiting.
+func newTool[A any](name string, fn func(context.Context, sessions.Session, mcpservice.ToolResponseWriter, *mcpservice.ToolRequest[A]) error, opts ...mcpservice.ToolOption) mcpservice.StaticTool {
+	return mcpservice.NewTool(name, func(ctx context.Context, s sessions.Session, w mcpservice.ToolResponseWriter, r *mcpservice.ToolRequest[A]) error {
+		ctx, span := tracer().Start(ctx, "tool "+name, trace.WithAttributes(
+			attribute.String("mcp.tool", name),
+			attribute.String("mcp.session_id", s.SessionID()),
+		))
+		err := fn(ctx, tracedSession{s}, tracedWriter{ToolResponseWriter: w, span: span}, r)
+		endSpan(span, err)
+		return err
+	}, opts...)
+}
+
+// tracedWriter marks the tool span failed when the tool reports an error
+// result.
+type tracedWriter struct {
+	mcpservice.ToolResponseWriter
+	span trace.Span
+}
+
+func (w tracedWriter) SetError(isError bool) {
+	if isError {
+		w.span.SetStatus(codes.Error, "tool error")
+	}
+	w.ToolResponseWriter.SetError(isError)
+}
+
+// tracedSession records spans for the session's data access and for the
+// elicitation and sampling requests sent through it.
+type tracedSession struct {
+	sessions.Session
+}
+
+func (s tracedSession) GetData(ctx context.Context, key string) ([]byte, bool, error) {
+	return tracedData{d: s.Session, backend: "session"}.GetData(ctx, key)
+}
+
+func (s tracedSession) PutData(ctx context.Context, key string, value []byte) error {
+	return tracedData{d: s.Session, backend: "session"}.PutData(ctx, key, value)
+}
+
+func (s tracedSession) DeleteData(ctx context.Context, key string) error {
+	return tracedData{d: s.Session, backend: "session"}.DeleteData(ctx, key)
+}
+
+func (s tracedSession) GetElicitationCapability() (sessions.ElicitationCapability, bool) {
+	c, ok := s.Session.GetElicitationCapability()
+	if !ok {
+		return nil, false
+	}
+	return tracedElicitation{c}, true
+}
+
+func (s tracedSession) GetSamplingCapability() (sessions.SamplingCapability, bool) {
+	c, ok := s.Session.GetSamplingCapability()
+	if !ok {
+		return nil, false
+	}
+	return tracedSampling{c}, true
+}
+
+// tracedData records a span for every read and write of d. backend names
+// where the data lives: the session or the user store.
+type tracedData struct {
+	d       gameData
+	backend string
+}
+
+func (t tracedData) start(ctx context.Context, op, key string) (context.Context, trace.Span) {
+	return tracer().Start(ctx, "data "+op, trace.WithAttributes(
+		attribute.String("data.backend", t.backend),
+		attribute.String("data.key", key),
+	))
+}
+
+func (t tracedData) GetData(ctx context.Context, key string) ([]byte, bool, error) {
+	ctx, span := t.start(ctx, "get", key)
+	v, found, err := t.d.GetData(ctx, key)
+	span.SetAttributes(attribute.Bool("data.found", found))
+	endSpan(span, err)
+	return v, found, err
+}
+
+func (t tracedData) PutData(ctx context.Context, key string, value []byte) error {
+	ctx, span := t.start(ctx, "put", key)
+	span.SetAttributes(attribute.Int("data.size", len(value)))
+	err := t.d.PutData(ctx, key, value)
+	endSpan(span, err)
+	return err
+}
+
+func (t tracedData) DeleteData(ctx context.Context, key string) error {
+	ctx, span := t.start(ctx, "delete", key)
+	err := t.d.DeleteData(ctx, key)
+	endSpan(span, err)
+	return err
+}
+
+type tracedElicitation struct {
+	sessions.ElicitationCapability
+}
+
+func (e tracedElicitation) Elicit(ctx context.Context, text string, subject any, opts ...sessions.ElicitOption) (sessions.ElicitAction, error) {
+	ctx, span := tracer().Start(ctx, "elicit")
+	action, err := e.ElicitationCapability.Elicit(ctx, text, subject, opts...)
+	span.SetAttributes(attribute.String("elicit.action", string(action)))
+	endSpan(span, err)
+	return action, err
+}
+
+type tracedSampling struct {
+	sessions.SamplingCapability
+}
+
+func (c tracedSampling) CreateMessage(ctx context.Context, system string, user sampling.Message, opts ...sampling.Option) (*sessions.SampleResult, error) {
+	ctx, span := tracer().Start(ctx, "sample")
+	res, err := c.SamplingCapability.CreateMessage(ctx, system, user, opts...)
+	if res != nil {
+		span.SetAttributes(
+			attribute.String("sample.model", res.Model),
+			attribute.String("sample.stop_reason", res.StopReason),
+		)
+	}
+	endSpan(span, err)
+	return res, err
+}
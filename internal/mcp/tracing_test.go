@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"slices"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTakeTurnIsTraced(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+
+	h := newHarness(t)
+	s, c := newFakeSession("")
+	h.mustCall(s, "start_game", StartGameArgs{})
+
+	c.elicit(accept("B2", ""))
+	c.sample(reply("A1"))
+	h.mustCall(s, "take_turn", TakeTurnArgs{})
+
+	var turn sdktrace.ReadOnlySpan
+	for _, span := range rec.Ended() {
+		if span.Name() == "tool take_turn" {
+			turn = span
+		}
+	}
+	if turn == nil {
+		t.Fatal("expected a span for the take_turn call")
+	}
+
+	var children []string
+	for _, span := range rec.Ended() {
+		if span.Parent().SpanID() == turn.SpanContext().SpanID() {
+			children = append(children, span.Name())
+		}
+	}
+	for _, name := range []string{"elicit", "sample", "data get", "data put"} {
+		if !slices.Contains(children, name) {
+			t.Errorf("expected a %q span inside take_turn, got %q", name, children)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ggoodman/mcp-server-go/mcpservice"
+	"github.com/ggoodman/mcp-server-go/sessions"
+)
+
+type UndoTurnArgs struct {
+	GameID string `json:"game_id,omitempty" jsonschema:"description=ID or name of the game to act on (default: the current game)"`
+}
+
+// takebackPolicy tracks how many rounds the user may take back in the
+// current game and how many they already have.
+type takebackPolicy struct {
+	Limit int `json:"limit"`
+	Used  int `json:"used"`
+}
+
+func (p takebackPolicy) remaining() int { return p.Limit - p.Used }
+
+// undoTurn rolls back the last round: the champion's reply and the user's
+// move (with its heckle) that prompted it.
+func (t *ticTacTuring) undoTurn(ctx context.Context, s sessions.Session, w mcpservice.ToolResponseWriter, r *mcpservice.ToolRequest[UndoTurnArgs]) error {
+	game, ok := t.loadActiveGame(ctx, s, w, r.Args().GameID)
+	if !ok {
+		return nil
+	}
+	gs := game.state
+	policy := &game.takebacks
+
+	w.SetMeta("takebackLimit", policy.Limit)
+	w.SetMeta("takebacksUsed", policy.Used)
+
+	if policy.remaining() <= 0 {
+		w.SetError(true)
+		w.AppendText(fmt.Sprintf("No takebacks left. This game allows %d and all have been used. Call take_turn to continue playing.", policy.Limit))
+		return nil
+	}
+
+	if len(gs.ToString()) <= game.setUpPlies {
+		w.SetError(true)
+		w.AppendText("There is nothing to take back yet. Call take_turn to make the first move.")
+		return nil
+	}
+
+	// Pop the champion's reply, then the user's move that prompted it. The
+	// moves of a custom starting position cannot be taken back.
+	for i := 0; i < 2 && len(gs.ToString()) > game.setUpPlies; i++ {
+		if _, err := gs.UndoMove(); err != nil {
+			break
+		}
+	}
+
+	if n := (len(gs.ToString()) + 1) / 2; len(game.heckles) > n {
+		game.heckles = game.heckles[:n]
+	}
+
+	policy.Used++
+
+	if err := game.save(ctx); err != nil {
+		w.SetError(true)
+		_ = w.AppendText("Failed to save game state")
+		return nil
+	}
+	w.SetMeta("takebacksUsed", policy.Used)
+
+	w.AppendText(fmt.Sprintf("The last round was taken back. Takebacks used: %d of %d.", policy.Used, policy.Limit))
+	w.AppendText("You MUST present the following game board to the user exactly as shown, with no alterations. Then immediately call the `take_turn` tool again (no extra commentary needed). This will allow the user to replay their move.")
+	w.AppendText("# Game state\n```text\n" + gs.BoardString() + "\n```")
+	appendBoardImage(w, gs)
+
+	return nil
+}
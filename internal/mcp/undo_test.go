@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUndoTurnTakesBackARound(t *testing.T) {
+	h := newHarness(t)
+	s, c := newFakeSession("")
+	h.mustCall(s, "start_game", StartGameArgs{})
+
+	c.elicit(accept("A1", "one"), accept("C1", "two"))
+	c.sample(reply("B2"), reply("B1"))
+	h.mustCall(s, "take_turn", TakeTurnArgs{})
+	h.mustCall(s, "take_turn", TakeTurnArgs{})
+
+	res := h.mustCall(s, "undo_turn", UndoTurnArgs{})
+	if !strings.Contains(resultText(res), "Takebacks used: 1 of 1") {
+		t.Fatalf("expected the takeback to be counted, got %q", resultText(res))
+	}
+	if res.Meta["takebackLimit"] != 1 || res.Meta["takebacksUsed"] != 1 {
+		t.Fatalf("expected the takeback policy in the result metadata, got %v", res.Meta)
+	}
+
+	g := h.currentGame(s)
+	if got := g.state.ToString(); got != "AE" {
+		t.Fatalf("expected both moves of the last round to be taken back, got %q", got)
+	}
+	if len(g.heckles) != 1 || g.heckles[0] != "one" {
+		t.Fatalf("expected the heckle of the taken back move to go, got %q", g.heckles)
+	}
+	if g.takebacks.Used != 1 {
+		t.Fatalf("expected the takeback to be stored, got %+v", g.takebacks)
+	}
+}
+
+func TestUndoTurnRefusesWhenLimitIsUsedUp(t *testing.T) {
+	h := newHarness(t)
+	s, c := newFakeSession("")
+	h.mustCall(s, "start_game", StartGameArgs{})
+
+	c.elicit(accept("A1", ""), accept("A1", ""))
+	c.sample(reply("B2"), reply("B2"))
+	h.mustCall(s, "take_turn", TakeTurnArgs{})
+	h.mustCall(s, "undo_turn", UndoTurnArgs{})
+	h.mustCall(s, "take_turn", TakeTurnArgs{})
+
+	res := h.call(s, "undo_turn", UndoTurnArgs{})
+	if !res.IsError || !strings.Contains(resultText(res), "No takebacks left") {
+		t.Fatalf("expected the takeback to be refused, got %q", resultText(res))
+	}
+	if res.Meta["takebackLimit"] != 1 || res.Meta["takebacksUsed"] != 1 {
+		t.Fatalf("expected the takeback policy in the result metadata, got %v", res.Meta)
+	}
+	if got := h.currentGame(s).state.ToString(); got != "AE" {
+		t.Fatalf("expected the game to be left alone, got %q", got)
+	}
+}
+
+func TestUndoTurnKeepsCustomPosition(t *testing.T) {
+	takebacks := 2
+	h := newHarness(t)
+	s, c := newFakeSession("")
+	h.mustCall(s, "start_game", StartGameArgs{Position: "AE", Takebacks: &takebacks})
+
+	res := h.call(s, "undo_turn", UndoTurnArgs{})
+	if !res.IsError || !strings.Contains(resultText(res), "nothing to take back") {
+		t.Fatalf("expected the set-up moves not to be taken back, got %q", resultText(res))
+	}
+
+	c.elicit(accept("C1", "gotcha"))
+	c.sample(reply("B1"))
+	h.mustCall(s, "take_turn", TakeTurnArgs{})
+	h.mustCall(s, "undo_turn", UndoTurnArgs{})
+
+	g := h.currentGame(s)
+	if got := g.state.ToString(); got != "AE" {
+		t.Fatalf("expected the custom position to be restored, got %q", got)
+	}
+	if len(g.heckles) != 1 || g.heckles[0] != "" {
+		t.Fatalf("expected only the set-up heckle placeholder to remain, got %q", g.heckles)
+	}
+	if g.takebacks.Used != 1 {
+		t.Fatalf("expected the refused takeback not to count, got %+v", g.takebacks)
+	}
+}
@@ -0,0 +1,123 @@
+// Package memhost provides an in-memory sessions.SessionHost for local
+// development and tests, where running Redis is not worth the trouble.
+//
+// It wraps memoryhost.Host from mcp-server-go, which names every session
+// after the process ID and so can only hold one session at a time. Host keeps
+// session metadata under the IDs chosen by the transport instead, so any
+// number of clients can connect.
+package memhost
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ggoodman/mcp-server-go/sessions"
+	"github.com/ggoodman/mcp-server-go/sessions/memoryhost"
+)
+
+var _ sessions.SessionHost = (*Host)(nil)
+
+// Host is an in-memory sessions.SessionHost. Messaging, events and session
+// data are handled by the embedded memoryhost.Host. All state is lost when
+// the process exits.
+type Host struct {
+	*memoryhost.Host
+
+	mu    sync.RWMutex
+	metas map[string]*sessions.SessionMetadata
+}
+
+// New returns an empty Host.
+func New() *Host {
+	return &Host{
+		Host:  memoryhost.New(),
+		metas: make(map[string]*sessions.SessionMetadata),
+	}
+}
+
+func (h *Host) CreateSession(ctx context.Context, meta *sessions.SessionMetadata) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if meta.SessionID == "" {
+		return errors.New("session ID is required")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, exists := h.metas[meta.SessionID]; exists {
+		return errors.New("session exists")
+	}
+	cp := *meta
+	h.metas[meta.SessionID] = &cp
+	return nil
+}
+
+func (h *Host) GetSession(ctx context.Context, sessionID string) (*sessions.SessionMetadata, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	m, ok := h.metas[sessionID]
+	if !ok {
+		return nil, sessions.ErrSessionNotFound
+	}
+	cp := *m
+	return &cp, nil
+}
+
+func (h *Host) MutateSession(ctx context.Context, sessionID string, fn func(*sessions.SessionMetadata) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	m, ok := h.metas[sessionID]
+	if !ok {
+		return sessions.ErrSessionNotFound
+	}
+	// Mutate a copy so that a failing fn leaves the session untouched.
+	cp := *m
+	if err := fn(&cp); err != nil {
+		return err
+	}
+	cp.UpdatedAt = time.Now().UTC()
+	h.metas[sessionID] = &cp
+	return nil
+}
+
+func (h *Host) TouchSession(ctx context.Context, sessionID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	m, ok := h.metas[sessionID]
+	if !ok {
+		return sessions.ErrSessionNotFound
+	}
+	now := time.Now().UTC()
+	m.LastAccess = now
+	m.UpdatedAt = now
+	return nil
+}
+
+// DeleteSession forgets the session's metadata and, through the embedded
+// host, its message stream and data. Deleting an unknown session is not an
+// error.
+func (h *Host) DeleteSession(ctx context.Context, sessionID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	delete(h.metas, sessionID)
+	h.mu.Unlock()
+	return h.Host.DeleteSession(ctx, sessionID)
+}
@@ -0,0 +1,68 @@
+package memhost
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ggoodman/mcp-server-go/sessions"
+	"github.com/ggoodman/mcp-server-go/sessions/sessionhosttest"
+)
+
+func TestSessionHost(t *testing.T) {
+	sessionhosttest.RunSessionHostTests(t, func(t *testing.T) sessions.SessionHost { return New() })
+}
+
+func TestManySessions(t *testing.T) {
+	ctx := context.Background()
+	h := New()
+	for _, id := range []string{"a", "b"} {
+		if err := h.CreateSession(ctx, &sessions.SessionMetadata{SessionID: id, UserID: "user-" + id}); err != nil {
+			t.Fatalf("CreateSession(%s): %v", id, err)
+		}
+	}
+	if err := h.CreateSession(ctx, &sessions.SessionMetadata{SessionID: "a"}); err == nil {
+		t.Fatal("expected a duplicate session to be rejected")
+	}
+
+	m, err := h.GetSession(ctx, "b")
+	if err != nil || m.UserID != "user-b" {
+		t.Fatalf("expected session b, got %+v (%v)", m, err)
+	}
+
+	if err := h.PutSessionData(ctx, "a", "k", []byte("v")); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.DeleteSession(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.GetSession(ctx, "a"); !errors.Is(err, sessions.ErrSessionNotFound) {
+		t.Fatalf("expected session a to be gone, got %v", err)
+	}
+	if _, found, _ := h.GetSessionData(ctx, "a", "k"); found {
+		t.Fatal("expected the data of session a to be deleted with it")
+	}
+	if _, err := h.GetSession(ctx, "b"); err != nil {
+		t.Fatalf("expected session b to remain, got %v", err)
+	}
+}
+
+func TestMutateSessionFailureLeavesSessionUntouched(t *testing.T) {
+	ctx := context.Background()
+	h := New()
+	if err := h.CreateSession(ctx, &sessions.SessionMetadata{SessionID: "a", UserID: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	boom := errors.New("boom")
+	err := h.MutateSession(ctx, "a", func(m *sessions.SessionMetadata) error {
+		m.UserID = "mallory"
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected the mutation error, got %v", err)
+	}
+	if m, _ := h.GetSession(ctx, "a"); m.UserID != "alice" {
+		t.Fatalf("expected the session to be unchanged, got %+v", m)
+	}
+}
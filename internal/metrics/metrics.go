@@ -0,0 +1,180 @@
+// Package metrics instruments the Tic-Tac-Turing server with Prometheus
+// counters and histograms. All methods are safe to call on a nil *Metrics,
+// which records nothing, so instrumentation never needs guarding.
+package metrics
+
+import (
+	"time"
+
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "tictacturing"
+
+// Kinds of games, for GameStarted.
+const (
+	KindStandard = "standard"
+	KindCustom   = "custom"
+	KindPuzzle   = "puzzle"
+)
+
+// Purposes of sampling requests.
+const (
+	SampleMove      = "move"
+	SampleDrawOffer = "draw_offer"
+)
+
+// Reasons a turn is aborted or cut short.
+const (
+	AbortInvalidMoves       = "invalid_moves"
+	AbortChampionConfused   = "champion_confused"
+	AbortElicitationTimeout = "elicitation_timeout"
+	AbortSamplingTimeout    = "sampling_timeout"
+)
+
+// Metrics holds the server's collectors.
+type Metrics struct {
+	gamesStarted        *prometheus.CounterVec
+	gamesFinished       *prometheus.CounterVec
+	elicitationDeclines *prometheus.CounterVec
+	samplingDuration    *prometheus.HistogramVec
+	samplingFailures    *prometheus.CounterVec
+	invalidModelMoves   prometheus.Counter
+	turnsAborted        *prometheus.CounterVec
+	rateLimited         *prometheus.CounterVec
+}
+
+// New creates the collectors and registers them with reg.
+func New(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		gamesStarted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "games_started_total",
+			Help:      "Games started, by kind (standard, custom or puzzle).",
+		}, []string{"kind"}),
+		gamesFinished: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "games_finished_total",
+			Help:      "Games finished, by outcome and by how they ended.",
+		}, []string{"outcome", "termination"}),
+		elicitationDeclines: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "elicitation_declines_total",
+			Help:      "Move elicitations the user declined or cancelled, by action.",
+		}, []string{"action"}),
+		samplingDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "sampling_duration_seconds",
+			Help:      "Latency of sampling requests to the host's model, by purpose.",
+			Buckets:   []float64{0.25, 0.5, 1, 2, 4, 8, 15, 30, 60},
+		}, []string{"purpose"}),
+		samplingFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "sampling_failures_total",
+			Help:      "Sampling requests that returned an error, by purpose.",
+		}, []string{"purpose"}),
+		invalidModelMoves: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "invalid_model_moves_total",
+			Help:      "Champion replies that were not a legal move.",
+		}),
+		turnsAborted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "turns_aborted_total",
+			Help:      "Turns aborted after exhausting their retries or missing a deadline, by reason.",
+		}, []string{"reason"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rate_limited_total",
+			Help:      "Calls refused by a rate limit or an exhausted sampling budget, by tool and scope.",
+		}, []string{"tool", "scope"}),
+	}
+	reg.MustRegister(
+		m.gamesStarted,
+		m.gamesFinished,
+		m.elicitationDeclines,
+		m.samplingDuration,
+		m.samplingFailures,
+		m.invalidModelMoves,
+		m.turnsAborted,
+		m.rateLimited,
+	)
+	return m
+}
+
+func (m *Metrics) GameStarted(kind string) {
+	if m == nil {
+		return
+	}
+	m.gamesStarted.WithLabelValues(kind).Inc()
+}
+
+// GameFinished counts a finished game. result is a ticktacktoe.Result*
+// token and termination an archive.Termination* reason, or "" for games
+// that ended on the board.
+func (m *Metrics) GameFinished(result, termination string) {
+	if m == nil {
+		return
+	}
+	if termination == "" {
+		termination = "board"
+	}
+	m.gamesFinished.WithLabelValues(outcomeLabel(result), termination).Inc()
+}
+
+// outcomeLabel names a result from the human's point of view.
+func outcomeLabel(result string) string {
+	switch result {
+	case ticktacktoe.ResultXWins:
+		return "human_win"
+	case ticktacktoe.ResultOWins:
+		return "champion_win"
+	case ticktacktoe.ResultDraw:
+		return "draw"
+	default:
+		return "unfinished"
+	}
+}
+
+func (m *Metrics) ElicitationDeclined(action string) {
+	if m == nil {
+		return
+	}
+	m.elicitationDeclines.WithLabelValues(action).Inc()
+}
+
+// Sampled records a sampling request that took d and failed when err is
+// non-nil.
+func (m *Metrics) Sampled(purpose string, d time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.samplingDuration.WithLabelValues(purpose).Observe(d.Seconds())
+	if err != nil {
+		m.samplingFailures.WithLabelValues(purpose).Inc()
+	}
+}
+
+func (m *Metrics) InvalidModelMoves(n int) {
+	if m == nil || n <= 0 {
+		return
+	}
+	m.invalidModelMoves.Add(float64(n))
+}
+
+func (m *Metrics) TurnAborted(reason string) {
+	if m == nil {
+		return
+	}
+	m.turnsAborted.WithLabelValues(reason).Inc()
+}
+
+// RateLimited counts a call to tool refused by the limit of scope: user, ip
+// or budget.
+func (m *Metrics) RateLimited(tool, scope string) {
+	if m == nil {
+		return
+	}
+	m.rateLimited.WithLabelValues(tool, scope).Inc()
+}
@@ -0,0 +1,36 @@
+// Package pseudonym derives the public names of players, so that the user
+// IDs issued by the identity provider never leave storage.
+package pseudonym
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Namer names players with a keyed hash of their user ID. Names are stable
+// for as long as the key is, and cannot be linked back to a user ID without
+// it. A nil *Namer uses an empty key.
+type Namer struct {
+	key []byte
+}
+
+// New returns a Namer keyed with key.
+func New(key []byte) *Namer {
+	return &Namer{key: key}
+}
+
+// Name returns the public name of the user with the given ID, or "" for
+// anonymous players.
+func (n *Namer) Name(userID string) string {
+	if userID == "" {
+		return ""
+	}
+	var key []byte
+	if n != nil {
+		key = n.key
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(userID))
+	return "player-" + hex.EncodeToString(mac.Sum(nil)[:5])
+}
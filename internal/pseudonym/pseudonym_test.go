@@ -0,0 +1,29 @@
+package pseudonym
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestName(t *testing.T) {
+	n := New([]byte("secret"))
+	name := n.Name("google-oauth2|1234567890")
+	if !strings.HasPrefix(name, "player-") || len(name) != len("player-")+10 {
+		t.Fatalf("unexpected name %q", name)
+	}
+	if strings.Contains(name, "1234567890") {
+		t.Fatalf("expected the name not to contain the user ID, got %q", name)
+	}
+	if again := n.Name("google-oauth2|1234567890"); again != name {
+		t.Fatalf("expected a stable name, got %q then %q", name, again)
+	}
+	if other := New([]byte("other")).Name("google-oauth2|1234567890"); other == name {
+		t.Fatal("expected names to depend on the key")
+	}
+	if n.Name("") != "" {
+		t.Fatal("expected anonymous players to have no name")
+	}
+	if (*Namer)(nil).Name("alice") == "" {
+		t.Fatal("expected a nil Namer to name players")
+	}
+}
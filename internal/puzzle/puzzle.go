@@ -0,0 +1,94 @@
+// Package puzzle provides the daily challenge: a curated mid-game position,
+// chosen deterministically by date, from which the human (X) must beat the
+// champion within a limited number of moves.
+package puzzle
+
+import (
+	"fmt"
+	"time"
+
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+)
+
+// DateFormat is the layout of puzzle dates, which also serve as puzzle IDs.
+const DateFormat = time.DateOnly
+
+// Puzzle is the challenge of one day.
+type Puzzle struct {
+	// Date is the day of the puzzle in DateFormat.
+	Date string `json:"date"`
+	// Seed is the starting position in the GameState.ToString encoding. X is
+	// always to move.
+	Seed string `json:"seed"`
+	// Within is how many X moves the human may make to win.
+	Within int `json:"within"`
+}
+
+// State returns the starting position of the puzzle.
+func (p Puzzle) State() (*ticktacktoe.GameState, error) {
+	return ticktacktoe.GameStateFromString(p.Seed)
+}
+
+// seed is a curated position in which X to move can force a win within the
+// given number of moves, but not immediately.
+type seed struct {
+	moves  string
+	within int
+}
+
+// catalog is cycled through one entry per day. Appending entries reshuffles
+// future puzzles but never the ones already played, as long as epoch stays put.
+var catalog = []seed{
+	{"ABCG", 2},
+	{"AFHI", 3},
+	{"ABDG", 2},
+	{"BDFE", 2},
+	{"ACFI", 2},
+	{"BG", 3},
+	{"ABFD", 2},
+	{"ACHE", 2},
+	{"BAFD", 3},
+	{"ABHE", 2},
+	{"ACBG", 2},
+	{"AI", 3},
+	{"ABFI", 2},
+	{"AFEI", 2},
+	{"BDFG", 3},
+	{"BADC", 2},
+	{"ABCH", 2},
+	{"ABFCHD", 2},
+	{"AC", 3},
+	{"ACFGHI", 2},
+}
+
+// epoch is the date of the first puzzle.
+var epoch = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// ForDate returns the puzzle of the UTC day containing t.
+func ForDate(t time.Time) Puzzle {
+	day := t.UTC().Truncate(24 * time.Hour)
+	n := int(day.Sub(epoch).Hours() / 24)
+	s := catalog[((n%len(catalog))+len(catalog))%len(catalog)]
+	return Puzzle{Date: day.Format(DateFormat), Seed: s.moves, Within: s.within}
+}
+
+// Solved reports whether a finished attempt at p, ending in gs with the
+// given result token, solved the puzzle: X won having made at most Within
+// moves of their own.
+func (p Puzzle) Solved(gs *ticktacktoe.GameState, result string) bool {
+	return result == ticktacktoe.ResultXWins && p.MovesMade(gs) <= p.Within
+}
+
+// MovesMade returns how many X moves have been played in gs since the
+// puzzle's starting position.
+func (p Puzzle) MovesMade(gs *ticktacktoe.GameState) int {
+	return (len(gs.ToString()) - len(p.Seed) + 1) / 2
+}
+
+// String describes the goal of the puzzle.
+func (p Puzzle) String() string {
+	if p.Within == 1 {
+		return fmt.Sprintf("Daily puzzle %s: X to move and win with the next move.", p.Date)
+	}
+	return fmt.Sprintf("Daily puzzle %s: X to move and win within %d moves.", p.Date, p.Within)
+}
@@ -0,0 +1,79 @@
+package puzzle
+
+import (
+	"testing"
+	"time"
+
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+)
+
+func TestCatalogPuzzlesAreForcedWins(t *testing.T) {
+	for _, s := range catalog {
+		gs, err := ticktacktoe.GameStateFromString(s.moves)
+		if err != nil {
+			t.Fatalf("%s: %v", s.moves, err)
+		}
+		if gs.PlayerToMove() != 'X' {
+			t.Fatalf("%s: expected X to move", s.moves)
+		}
+		if len(gs.ThreatSquares('X')) > 0 {
+			t.Fatalf("%s: X can win immediately", s.moves)
+		}
+		outcome, plies := gs.Evaluate()
+		if outcome != ticktacktoe.OutcomeWin || (plies+1)/2 > s.within {
+			t.Fatalf("%s: expected a forced win within %d moves, got %s in %d plies", s.moves, s.within, outcome, plies)
+		}
+	}
+}
+
+func TestForDateIsDeterministic(t *testing.T) {
+	morning := time.Date(2026, time.March, 3, 1, 0, 0, 0, time.UTC)
+	evening := time.Date(2026, time.March, 3, 23, 0, 0, 0, time.UTC)
+	a, b := ForDate(morning), ForDate(evening)
+	if a != b || a.Date != "2026-03-03" {
+		t.Fatalf("expected the same puzzle all day, got %+v and %+v", a, b)
+	}
+	if next := ForDate(morning.AddDate(0, 0, 1)); next.Seed == a.Seed {
+		t.Fatalf("expected consecutive days to differ, got %s twice", a.Seed)
+	}
+	if early := ForDate(epoch.AddDate(0, 0, -1)); early.Seed != catalog[len(catalog)-1].moves {
+		t.Fatalf("expected dates before the epoch to wrap around, got %s", early.Seed)
+	}
+}
+
+func TestSolved(t *testing.T) {
+	p := Puzzle{Seed: "ABCG", Within: 2}
+	won, _ := ticktacktoe.GameStateFromString("ABCGEDI") // X wins on the diagonal after 2 moves
+	if !p.Solved(won, won.Result()) {
+		t.Fatalf("expected %s to solve the puzzle", won.ToString())
+	}
+	if p.Solved(won, ticktacktoe.ResultOWins) {
+		t.Fatal("expected a loss not to solve the puzzle")
+	}
+	if (Puzzle{Seed: "ABCG", Within: 1}).Solved(won, won.Result()) {
+		t.Fatal("expected a win after the move limit not to solve the puzzle")
+	}
+}
+
+func TestStatsStreaks(t *testing.T) {
+	var s Stats
+	s.Record("2026-03-01", true)
+	s.Record("2026-03-02", true)
+	s.Record("2026-03-02", false) // already played
+	if s.Streak != 2 || s.Solved != 2 || s.Attempted != 2 {
+		t.Fatalf("unexpected stats after two solves: %+v", s)
+	}
+
+	s.Record("2026-03-04", true) // skipped a day
+	if s.Streak != 1 || s.BestStreak != 2 {
+		t.Fatalf("expected a missed day to reset the streak: %+v", s)
+	}
+
+	s.Record("2026-03-05", false)
+	if s.Streak != 0 || s.BestStreak != 2 || s.Attempted != 4 || s.Solved != 3 {
+		t.Fatalf("expected a failure to reset the streak: %+v", s)
+	}
+	if !s.Played("2026-03-05") || s.Played("2026-03-06") {
+		t.Fatalf("unexpected history %+v", s.History)
+	}
+}
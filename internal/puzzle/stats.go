@@ -0,0 +1,73 @@
+package puzzle
+
+import "time"
+
+// maxHistory bounds how many past results Stats keeps.
+const maxHistory = 30
+
+// Result is the outcome of one daily puzzle.
+type Result struct {
+	Date   string `json:"date"`
+	Solved bool   `json:"solved"`
+}
+
+// Stats tracks a player's daily puzzle results. A streak counts consecutive
+// days solved; missing a day or failing a puzzle resets it.
+type Stats struct {
+	Attempted  int `json:"attempted"`
+	Solved     int `json:"solved"`
+	Streak     int `json:"streak"`
+	BestStreak int `json:"bestStreak"`
+	// History holds the most recent results, newest last.
+	History []Result `json:"history,omitempty"`
+}
+
+// Played reports whether a result for date has been recorded.
+func (s *Stats) Played(date string) bool {
+	for _, r := range s.History {
+		if r.Date == date {
+			return true
+		}
+	}
+	return false
+}
+
+// Record adds the result of the puzzle of date. Recording a date twice has
+// no effect.
+func (s *Stats) Record(date string, solved bool) {
+	if s.Played(date) {
+		return
+	}
+
+	s.Attempted++
+	switch {
+	case !solved:
+		s.Streak = 0
+	case s.solvedDayBefore(date):
+		s.Solved++
+		s.Streak++
+	default:
+		s.Solved++
+		s.Streak = 1
+	}
+	s.BestStreak = max(s.BestStreak, s.Streak)
+
+	s.History = append(s.History, Result{Date: date, Solved: solved})
+	if len(s.History) > maxHistory {
+		s.History = s.History[len(s.History)-maxHistory:]
+	}
+}
+
+// solvedDayBefore reports whether the last recorded result is a solve of the
+// day before date.
+func (s *Stats) solvedDayBefore(date string) bool {
+	if len(s.History) == 0 {
+		return false
+	}
+	last := s.History[len(s.History)-1]
+	d, err := time.Parse(DateFormat, date)
+	if err != nil {
+		return false
+	}
+	return last.Solved && last.Date == d.AddDate(0, 0, -1).Format(DateFormat)
+}
@@ -0,0 +1,181 @@
+// Package ratelimit limits how often keyed clients, such as users or IP
+// addresses, may do something. Each key has its own token bucket.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Rate allows N events per period Per, in bursts of up to N. The zero Rate
+// allows everything.
+type Rate struct {
+	N   int
+	Per time.Duration
+}
+
+// ParseRate parses a rate written as N/period, such as 20/h, 5/10m or
+// 500/24h. An empty string or "0" is the zero Rate.
+func ParseRate(s string) (Rate, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return Rate{}, nil
+	}
+	count, per, ok := strings.Cut(s, "/")
+	if !ok {
+		return Rate{}, fmt.Errorf("invalid rate %q: expected N/period", s)
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 0 {
+		return Rate{}, fmt.Errorf("invalid rate %q: bad count", s)
+	}
+	if per != "" && (per[0] < '0' || per[0] > '9') {
+		// A bare unit, as in 20/h, means one of it.
+		per = "1" + per
+	}
+	d, err := time.ParseDuration(per)
+	if err != nil || d <= 0 {
+		return Rate{}, fmt.Errorf("invalid rate %q: bad period", s)
+	}
+	if n == 0 {
+		return Rate{}, nil
+	}
+	return Rate{N: n, Per: d}, nil
+}
+
+// Decode implements envdecode.Decoder.
+func (r *Rate) Decode(s string) error {
+	v, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
+
+func (r Rate) String() string {
+	if r.IsZero() {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d/%s", r.N, r.Per)
+}
+
+func (r Rate) IsZero() bool {
+	return r.N <= 0 || r.Per <= 0
+}
+
+// Limiter enforces a Rate for each key. A nil *Limiter allows everything.
+type Limiter struct {
+	rate Rate
+
+	mu        sync.Mutex
+	buckets   map[string]*rate.Limiter
+	lastSweep time.Time
+	// now is replaced by tests.
+	now func() time.Time
+}
+
+// New returns a limiter enforcing r per key, or nil when r is zero.
+func New(r Rate) *Limiter {
+	if r.IsZero() {
+		return nil
+	}
+	return &Limiter{rate: r, buckets: map[string]*rate.Limiter{}, now: time.Now}
+}
+
+// Rate returns the rate enforced by l.
+func (l *Limiter) Rate() Rate {
+	if l == nil {
+		return Rate{}
+	}
+	return l.rate
+}
+
+// Allow reports whether key may do one more thing now, and counts it if so.
+func (l *Limiter) Allow(key string) bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	return l.bucket(key, now).AllowN(now, 1)
+}
+
+// Reservation is a token taken by Reserve, which can be handed back. A nil
+// *Reservation holds nothing.
+type Reservation struct {
+	l  *Limiter
+	r  *rate.Reservation
+	at time.Time
+}
+
+// Reserve reports whether key may do one more thing now and, if so, takes a
+// token for it like Allow. Cancel the reservation when the thing is not done
+// after all, e.g. because another limit refused it.
+func (l *Limiter) Reserve(key string) (*Reservation, bool) {
+	if l == nil {
+		return nil, true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	r := l.bucket(key, now).ReserveN(now, 1)
+	if !r.OK() {
+		return nil, false
+	}
+	if r.DelayFrom(now) > 0 {
+		r.CancelAt(now)
+		return nil, false
+	}
+	return &Reservation{l: l, r: r, at: now}, true
+}
+
+// Cancel returns the reserved token.
+func (r *Reservation) Cancel() {
+	if r == nil {
+		return
+	}
+	r.l.mu.Lock()
+	defer r.l.mu.Unlock()
+	// The token was taken for the moment of reservation; cancelling at any
+	// later time would hand back nothing.
+	r.r.CancelAt(r.at)
+}
+
+// Tokens returns how many more things key may do now.
+func (l *Limiter) Tokens(key string) float64 {
+	if l == nil {
+		return math.Inf(1)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	return l.bucket(key, now).TokensAt(now)
+}
+
+// bucket returns the bucket of key, creating it full. Once per period it
+// drops the buckets that have refilled, which behave exactly like new ones,
+// so idle keys do not accumulate.
+func (l *Limiter) bucket(key string, now time.Time) *rate.Limiter {
+	if now.Sub(l.lastSweep) >= l.rate.Per {
+		for k, b := range l.buckets {
+			if b.TokensAt(now) >= float64(l.rate.N) {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = rate.NewLimiter(rate.Every(l.rate.Per/time.Duration(l.rate.N)), l.rate.N)
+		l.buckets[key] = b
+	}
+	return b
+}
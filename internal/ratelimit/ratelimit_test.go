@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRate(t *testing.T) {
+	for in, want := range map[string]Rate{
+		"":        {},
+		"0":       {},
+		"0/h":     {},
+		"20/h":    {N: 20, Per: time.Hour},
+		"5/10m":   {N: 5, Per: 10 * time.Minute},
+		"500/24h": {N: 500, Per: 24 * time.Hour},
+	} {
+		got, err := ParseRate(in)
+		if err != nil || got != want {
+			t.Errorf("ParseRate(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"20", "x/h", "-1/h", "20/", "20/0s", "20/fortnight"} {
+		if _, err := ParseRate(in); err == nil {
+			t.Errorf("ParseRate(%q) succeeded, want an error", in)
+		}
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	l := New(Rate{N: 2, Per: time.Minute})
+	l.now = func() time.Time { return now }
+
+	if !l.Allow("alice") || !l.Allow("alice") {
+		t.Fatal("expected a full burst to be allowed")
+	}
+	if l.Allow("alice") {
+		t.Fatal("expected the third call within the period to be refused")
+	}
+	if !l.Allow("bob") {
+		t.Fatal("expected keys to have their own buckets")
+	}
+
+	now = now.Add(30 * time.Second)
+	if !l.Allow("alice") || l.Allow("alice") {
+		t.Fatal("expected one call to be allowed after half a period")
+	}
+
+	// A period later every bucket has refilled and is swept away.
+	now = now.Add(2 * time.Minute)
+	if n := l.Tokens("carol"); n != 2 {
+		t.Fatalf("expected a new key to have a full bucket, got %v", n)
+	}
+	if len(l.buckets) != 1 {
+		t.Fatalf("expected refilled buckets to be dropped, got %d buckets", len(l.buckets))
+	}
+}
+
+func TestReserve(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	l := New(Rate{N: 2, Per: time.Minute})
+	l.now = func() time.Time { return now }
+
+	r, ok := l.Reserve("alice")
+	if !ok {
+		t.Fatal("expected a token to be reserved")
+	}
+	if n := l.Tokens("alice"); n != 1 {
+		t.Fatalf("expected the reservation to take a token, got %v left", n)
+	}
+	now = now.Add(time.Second)
+	r.Cancel()
+	if n := l.Tokens("alice"); n != 2 {
+		t.Fatalf("expected a cancelled reservation to hand its token back, got %v left", n)
+	}
+
+	l.Allow("alice")
+	l.Allow("alice")
+	if _, ok := l.Reserve("alice"); ok {
+		t.Fatal("expected no reservation once the bucket is empty")
+	}
+	if n := l.Tokens("alice"); n != 0 {
+		t.Fatalf("expected a refused reservation to take nothing, got %v left", n)
+	}
+}
+
+func TestNilLimiterAllowsEverything(t *testing.T) {
+	l := New(Rate{})
+	if l != nil {
+		t.Fatal("expected no limiter for the zero rate")
+	}
+	for range 100 {
+		if !l.Allow("alice") {
+			t.Fatal("expected a nil limiter to allow everything")
+		}
+		r, ok := l.Reserve("alice")
+		if !ok {
+			t.Fatal("expected a nil limiter to allow everything")
+		}
+		r.Cancel()
+	}
+}
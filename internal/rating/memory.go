@@ -0,0 +1,64 @@
+package rating
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-process Store. Ratings are lost when the process exits.
+type MemoryStore struct {
+	mu      sync.Mutex
+	ratings map[Kind]map[string]Rating
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{ratings: map[Kind]map[string]Rating{
+		KindUser:     {},
+		KindChampion: {},
+	}}
+}
+
+func (m *MemoryStore) get(kind Kind, player string) Rating {
+	if r, ok := m.ratings[kind][player]; ok {
+		return r
+	}
+	return New(kind, player)
+}
+
+func (m *MemoryStore) GetRating(ctx context.Context, kind Kind, player string) (Rating, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.get(kind, player), nil
+}
+
+func (m *MemoryStore) RecordGame(ctx context.Context, user, champion string, score float64) (Rating, Rating, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u, c := Apply(m.get(KindUser, user), m.get(KindChampion, champion), score, time.Now().UTC())
+	m.ratings[KindUser][user] = u
+	m.ratings[KindChampion][champion] = c
+	return u, c, nil
+}
+
+func (m *MemoryStore) Leaderboard(ctx context.Context, kind Kind, n int) ([]Rating, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]Rating, 0, len(m.ratings[kind]))
+	for _, r := range m.ratings[kind] {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Rating != out[j].Rating {
+			return out[i].Rating > out[j].Rating
+		}
+		return out[i].Player < out[j].Player
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out, nil
+}
@@ -0,0 +1,119 @@
+// Package rating maintains Elo ratings for the humans who challenge the
+// champion and for the champion configurations (persona and model) they
+// play against, so that beating a strong champion counts for more than
+// beating a weak one.
+package rating
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Kind distinguishes human players from champion configurations.
+type Kind string
+
+const (
+	KindUser     Kind = "user"
+	KindChampion Kind = "champion"
+)
+
+// Elo parameters.
+const (
+	// Initial is the rating of a player with no rated games.
+	Initial = 1500.0
+	// ProvisionalGames is how many games a player plays with the larger
+	// ProvisionalK before settling down to K.
+	ProvisionalGames = 20
+	ProvisionalK     = 40.0
+	K                = 20.0
+)
+
+// Game scores from the user's point of view.
+const (
+	ScoreLoss = 0.0
+	ScoreDraw = 0.5
+	ScoreWin  = 1.0
+)
+
+// Rating is the rating of one player.
+type Rating struct {
+	Kind Kind `json:"kind"`
+	// Player identifies the player within its kind: the user ID for users
+	// and ChampionID for champions.
+	Player    string    `json:"player"`
+	Rating    float64   `json:"rating"`
+	Games     int       `json:"games"`
+	Wins      int       `json:"wins"`
+	Draws     int       `json:"draws"`
+	Losses    int       `json:"losses"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+}
+
+// New returns the rating of a player with no rated games.
+func New(kind Kind, player string) Rating {
+	return Rating{Kind: kind, Player: player, Rating: Initial}
+}
+
+// ChampionID identifies a champion configuration. Games played before
+// personas existed, or whose model was not reported, fall back to "default"
+// and "unknown".
+func ChampionID(persona, model string) string {
+	if persona == "" {
+		persona = "default"
+	}
+	if model == "" {
+		model = "unknown"
+	}
+	return persona + "/" + model
+}
+
+// Expected returns the expected score of a player rated a against one rated b.
+func Expected(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// k returns the update factor of r.
+func (r Rating) k() float64 {
+	if r.Games < ProvisionalGames {
+		return ProvisionalK
+	}
+	return K
+}
+
+// record adds a game with the given score to r against an opponent rated
+// opponent.
+func (r *Rating) record(opponent, score float64, at time.Time) {
+	r.Rating += r.k() * (score - Expected(r.Rating, opponent))
+	r.Games++
+	switch score {
+	case ScoreWin:
+		r.Wins++
+	case ScoreLoss:
+		r.Losses++
+	default:
+		r.Draws++
+	}
+	r.UpdatedAt = at
+}
+
+// Apply returns the ratings of a user and a champion after a game in which
+// the user scored score (ScoreWin, ScoreDraw or ScoreLoss).
+func Apply(user, champion Rating, score float64, at time.Time) (Rating, Rating) {
+	u, c := user, champion
+	u.record(champion.Rating, score, at)
+	c.record(user.Rating, 1-score, at)
+	return u, c
+}
+
+// Store persists ratings. Implementations MUST be safe for concurrent use.
+type Store interface {
+	// GetRating returns the player's rating, or New(kind, player) if the
+	// player has no rated games.
+	GetRating(ctx context.Context, kind Kind, player string) (Rating, error)
+	// RecordGame atomically applies a finished game to the ratings of the
+	// user and the champion and returns their new ratings.
+	RecordGame(ctx context.Context, user, champion string, score float64) (Rating, Rating, error)
+	// Leaderboard returns up to n players of the given kind, best first.
+	Leaderboard(ctx context.Context, kind Kind, n int) ([]Rating, error)
+}
@@ -0,0 +1,77 @@
+package rating
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestExpected(t *testing.T) {
+	if got := Expected(1500, 1500); got != 0.5 {
+		t.Fatalf("expected 0.5 between equals, got %v", got)
+	}
+	if got := Expected(1900, 1500); math.Abs(got-0.909) > 0.001 {
+		t.Fatalf("expected ~0.909 for a 400 point favourite, got %v", got)
+	}
+}
+
+func TestApplyIsZeroSumBetweenEquals(t *testing.T) {
+	u, c := Apply(New(KindUser, "alice"), New(KindChampion, ChampionID("", "m")), ScoreWin, time.Now())
+	if u.Rating != Initial+ProvisionalK/2 || c.Rating != Initial-ProvisionalK/2 {
+		t.Fatalf("unexpected ratings %v and %v", u.Rating, c.Rating)
+	}
+	if u.Wins != 1 || u.Games != 1 || c.Losses != 1 || c.Games != 1 {
+		t.Fatalf("unexpected tallies %+v and %+v", u, c)
+	}
+}
+
+func TestApplyRewardsBeatingStrongerChampions(t *testing.T) {
+	user := New(KindUser, "alice")
+	weak, strong := New(KindChampion, "weak"), New(KindChampion, "strong")
+	strong.Rating = 1800
+
+	afterWeak, _ := Apply(user, weak, ScoreWin, time.Now())
+	afterStrong, _ := Apply(user, strong, ScoreWin, time.Now())
+	if afterStrong.Rating <= afterWeak.Rating {
+		t.Fatalf("expected a bigger gain against the stronger champion: %v vs %v", afterStrong.Rating, afterWeak.Rating)
+	}
+
+	drawn, _ := Apply(user, strong, ScoreDraw, time.Now())
+	if drawn.Rating <= Initial || drawn.Draws != 1 {
+		t.Fatalf("expected a draw against a stronger champion to gain rating, got %+v", drawn)
+	}
+}
+
+func TestMemoryStoreLeaderboard(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	champion := ChampionID("", "model")
+	if _, _, err := s.RecordGame(ctx, "alice", champion, ScoreWin); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.RecordGame(ctx, "bob", champion, ScoreLoss); err != nil {
+		t.Fatal(err)
+	}
+
+	top, err := s.Leaderboard(ctx, KindUser, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 2 || top[0].Player != "alice" || top[1].Player != "bob" {
+		t.Fatalf("unexpected leaderboard %+v", top)
+	}
+
+	r, err := s.GetRating(ctx, KindChampion, champion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Games != 2 || r.Wins != 1 || r.Losses != 1 {
+		t.Fatalf("unexpected champion rating %+v", r)
+	}
+
+	unrated, _ := s.GetRating(ctx, KindUser, "carol")
+	if unrated.Rating != Initial || unrated.Games != 0 {
+		t.Fatalf("expected a fresh rating, got %+v", unrated)
+	}
+}
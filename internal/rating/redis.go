@@ -0,0 +1,121 @@
+package rating
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore keeps each rating as a JSON document and ranks players of each
+// kind in a sorted set.
+type RedisStore struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+var _ Store = (*RedisStore)(nil)
+
+// maxRecordAttempts bounds the retries of RecordGame when another game
+// updates the same ratings concurrently.
+const maxRecordAttempts = 5
+
+// NewRedisStore returns a Store backed by the given client. Keys are
+// namespaced under keyPrefix so ratings can share a database with the
+// session host and the game archive.
+func NewRedisStore(client *redis.Client, keyPrefix string) *RedisStore {
+	return &RedisStore{client: client, keyPrefix: keyPrefix}
+}
+
+func (r *RedisStore) ratingKey(kind Kind, player string) string {
+	return r.keyPrefix + "rating:" + string(kind) + ":" + url.PathEscape(player)
+}
+
+func (r *RedisStore) leaderboardKey(kind Kind) string {
+	return r.keyPrefix + "ratings:" + string(kind)
+}
+
+func (r *RedisStore) get(ctx context.Context, c redis.Cmdable, kind Kind, player string) (Rating, error) {
+	b, err := c.Get(ctx, r.ratingKey(kind, player)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return New(kind, player), nil
+	}
+	if err != nil {
+		return Rating{}, fmt.Errorf("error loading rating: %w", err)
+	}
+	var rt Rating
+	if err := json.Unmarshal(b, &rt); err != nil {
+		return Rating{}, fmt.Errorf("error decoding rating: %w", err)
+	}
+	return rt, nil
+}
+
+func (r *RedisStore) GetRating(ctx context.Context, kind Kind, player string) (Rating, error) {
+	return r.get(ctx, r.client, kind, player)
+}
+
+func (r *RedisStore) RecordGame(ctx context.Context, user, champion string, score float64) (Rating, Rating, error) {
+	userKey, championKey := r.ratingKey(KindUser, user), r.ratingKey(KindChampion, champion)
+
+	var u, c Rating
+	update := func(tx *redis.Tx) error {
+		before, err := r.get(ctx, tx, KindUser, user)
+		if err != nil {
+			return err
+		}
+		opponent, err := r.get(ctx, tx, KindChampion, champion)
+		if err != nil {
+			return err
+		}
+		u, c = Apply(before, opponent, score, time.Now().UTC())
+
+		ub, err := json.Marshal(u)
+		if err != nil {
+			return fmt.Errorf("error encoding rating: %w", err)
+		}
+		cb, err := json.Marshal(c)
+		if err != nil {
+			return fmt.Errorf("error encoding rating: %w", err)
+		}
+		_, err = tx.TxPipelined(ctx, func(p redis.Pipeliner) error {
+			p.Set(ctx, userKey, ub, 0)
+			p.Set(ctx, championKey, cb, 0)
+			p.ZAdd(ctx, r.leaderboardKey(KindUser), redis.Z{Score: u.Rating, Member: user})
+			p.ZAdd(ctx, r.leaderboardKey(KindChampion), redis.Z{Score: c.Rating, Member: champion})
+			return nil
+		})
+		return err
+	}
+
+	for attempt := 0; attempt < maxRecordAttempts; attempt++ {
+		err := r.client.Watch(ctx, update, userKey, championKey)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return Rating{}, Rating{}, fmt.Errorf("error recording rated game: %w", err)
+		}
+		return u, c, nil
+	}
+	return Rating{}, Rating{}, errors.New("error recording rated game: too much contention")
+}
+
+func (r *RedisStore) Leaderboard(ctx context.Context, kind Kind, n int) ([]Rating, error) {
+	players, err := r.client.ZRevRange(ctx, r.leaderboardKey(kind), 0, int64(n-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error loading leaderboard: %w", err)
+	}
+	out := make([]Rating, 0, len(players))
+	for _, player := range players {
+		rt, err := r.get(ctx, r.client, kind, player)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rt)
+	}
+	return out, nil
+}
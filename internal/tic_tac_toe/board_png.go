@@ -0,0 +1,153 @@
+package ticktacktoe
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+)
+
+// Layout of the rendered board image, in pixels.
+const (
+	pngCellSize   = 120
+	pngMargin     = 36 // room for the column letters and row numbers
+	pngPadding    = 12
+	pngGridWidth  = 4
+	pngMarkInset  = 26
+	pngMarkStroke = 7
+	pngGlyphScale = 3
+)
+
+var (
+	pngBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	pngGridColor  = color.RGBA{0x33, 0x33, 0x33, 0xff}
+	pngLabelColor = color.RGBA{0x66, 0x66, 0x66, 0xff}
+	pngXColor     = color.RGBA{0x1f, 0x6f, 0xeb, 0xff}
+	pngOColor     = color.RGBA{0xd7, 0x3a, 0x49, 0xff}
+)
+
+// pngGlyphs is a tiny 5x7 bitmap font covering only the coordinate labels so
+// the image can be rendered without pulling in a font package.
+var pngGlyphs = map[rune][7]string{
+	'A': {".###.", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"},
+	'B': {"####.", "#...#", "#...#", "####.", "#...#", "#...#", "####."},
+	'C': {".###.", "#...#", "#....", "#....", "#....", "#...#", ".###."},
+	'1': {"..#..", ".##..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	'2': {".###.", "#...#", "....#", "...#.", "..#..", ".#...", "#####"},
+	'3': {"####.", "....#", "....#", ".###.", "....#", "....#", "####."},
+}
+
+// BoardPNG renders the board as a PNG image using the same A-C / 1-3
+// coordinate labels as BoardString. Unlike the ASCII form, the image survives
+// hosts that reflow or truncate fenced text.
+func (gs *GameState) BoardPNG() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, gs.BoardImage()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// BoardImage draws the board described in BoardPNG onto a new square RGBA
+// image, for callers that want to compose it into a larger picture.
+func (gs *GameState) BoardImage() *image.RGBA {
+	size := pngMargin + 3*pngCellSize + pngPadding
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	fillRect(img, img.Bounds(), pngBackground)
+
+	// Coordinate labels
+	glyphW, glyphH := 5*pngGlyphScale, 7*pngGlyphScale
+	for i := 0; i < 3; i++ {
+		center := pngMargin + i*pngCellSize + pngCellSize/2
+		drawGlyph(img, rune('A'+i), center-glyphW/2, (pngMargin-glyphH)/2, pngLabelColor)
+		drawGlyph(img, rune('1'+i), (pngMargin-glyphW)/2, center-glyphH/2, pngLabelColor)
+	}
+
+	// Grid, including the outer border to mirror BoardString
+	for i := 0; i <= 3; i++ {
+		offset := pngMargin + i*pngCellSize - pngGridWidth/2
+		fillRect(img, image.Rect(pngMargin, offset, pngMargin+3*pngCellSize, offset+pngGridWidth), pngGridColor)
+		fillRect(img, image.Rect(offset, pngMargin, offset+pngGridWidth, pngMargin+3*pngCellSize), pngGridColor)
+	}
+
+	// Marks
+	for idx := 0; idx < 9; idx++ {
+		ch := gs.at(idx)
+		x0 := float64(pngMargin + (idx%3)*pngCellSize)
+		y0 := float64(pngMargin + (idx/3)*pngCellSize)
+		switch ch {
+		case 'X':
+			lo, hi := float64(pngMarkInset), float64(pngCellSize-pngMarkInset)
+			drawLine(img, x0+lo, y0+lo, x0+hi, y0+hi, pngMarkStroke, pngXColor)
+			drawLine(img, x0+hi, y0+lo, x0+lo, y0+hi, pngMarkStroke, pngXColor)
+		case 'O':
+			c := float64(pngCellSize) / 2
+			drawRing(img, x0+c, y0+c, c-pngMarkInset, pngMarkStroke, pngOColor)
+		}
+	}
+
+	return img
+}
+
+func fillRect(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	r = r.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+func drawGlyph(img *image.RGBA, ch rune, x, y int, c color.RGBA) {
+	rows, ok := pngGlyphs[ch]
+	if !ok {
+		return
+	}
+	for gy, row := range rows {
+		for gx, bit := range row {
+			if bit != '#' {
+				continue
+			}
+			px, py := x+gx*pngGlyphScale, y+gy*pngGlyphScale
+			fillRect(img, image.Rect(px, py, px+pngGlyphScale, py+pngGlyphScale), c)
+		}
+	}
+}
+
+// drawLine paints a segment with round caps by testing each pixel in the
+// segment's bounding box against its distance to the segment.
+func drawLine(img *image.RGBA, x1, y1, x2, y2, width float64, c color.RGBA) {
+	half := width / 2
+	minX, maxX := int(math.Min(x1, x2)-half), int(math.Max(x1, x2)+half)+1
+	minY, maxY := int(math.Min(y1, y2)-half), int(math.Max(y1, y2)+half)+1
+	dx, dy := x2-x1, y2-y1
+	lenSq := dx*dx + dy*dy
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			px, py := float64(x)+0.5, float64(y)+0.5
+			t := 0.0
+			if lenSq > 0 {
+				t = math.Max(0, math.Min(1, ((px-x1)*dx+(py-y1)*dy)/lenSq))
+			}
+			if math.Hypot(px-(x1+t*dx), py-(y1+t*dy)) <= half {
+				if (image.Point{x, y}).In(img.Bounds()) {
+					img.SetRGBA(x, y, c)
+				}
+			}
+		}
+	}
+}
+
+func drawRing(img *image.RGBA, cx, cy, radius, width float64, c color.RGBA) {
+	half := width / 2
+	outer := radius + half
+	for y := int(cy - outer); y <= int(cy+outer)+1; y++ {
+		for x := int(cx - outer); x <= int(cx+outer)+1; x++ {
+			d := math.Hypot(float64(x)+0.5-cx, float64(y)+0.5-cy)
+			if math.Abs(d-radius) <= half && (image.Point{x, y}).In(img.Bounds()) {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package ticktacktoe
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+)
+
+func TestBoardPNG(t *testing.T) {
+	gs, _ := GameStateFromString("AE") // X:A1, O:B2
+	data, err := gs.BoardPNG()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	size := pngMargin + 3*pngCellSize + pngPadding
+	if b := img.Bounds(); b.Dx() != size || b.Dy() != size {
+		t.Fatalf("expected %dx%d image, got %v", size, size, b)
+	}
+
+	// The center of A1 is where the two strokes of the X cross.
+	c := pngMargin + pngCellSize/2
+	if r, g, b, _ := img.At(c, c).RGBA(); uint8(r>>8) != pngXColor.R || uint8(g>>8) != pngXColor.G || uint8(b>>8) != pngXColor.B {
+		t.Fatalf("expected X color at A1 center")
+	}
+	// The center of B2 is inside the O ring and must stay empty.
+	c2 := pngMargin + pngCellSize + pngCellSize/2
+	if r, g, b, _ := img.At(c2, c2).RGBA(); uint8(r>>8) != pngBackground.R || uint8(g>>8) != pngBackground.G || uint8(b>>8) != pngBackground.B {
+		t.Fatalf("expected background at B2 center")
+	}
+	// The top of the O ring.
+	top := pngMargin + pngCellSize + pngMarkInset
+	if r, g, b, _ := img.At(c2, top).RGBA(); uint8(r>>8) != pngOColor.R || uint8(g>>8) != pngOColor.G || uint8(b>>8) != pngOColor.B {
+		t.Fatalf("expected O color on B2 ring")
+	}
+}
@@ -0,0 +1,144 @@
+package ticktacktoe
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// gameStateJSONVersion is the current version of the JSON schema written by
+// MarshalJSON. Decoders reject versions they do not understand.
+const gameStateJSONVersion = 1
+
+// VariantStandard is the only supported game variant: 3x3, three in a row,
+// X moves first.
+const VariantStandard = "standard"
+
+// gameStateJSON is the versioned wire form of a GameState:
+//
+//	{
+//	  "version": 1,
+//	  "variant": "standard",
+//	  "board": "X...O....",
+//	  "moves": ["A1", "B2"],
+//	  "winner": "",
+//	  "draw": false,
+//	  "toMove": "X"
+//	}
+//
+// board lists the squares A1..C3 row by row using 'X', 'O' or '.' for empty.
+// winner and toMove are "X", "O" or "" (no winner yet / game over). moves is
+// the source of truth; the remaining fields are derived and only checked for
+// consistency when decoding.
+type gameStateJSON struct {
+	Version int      `json:"version"`
+	Variant string   `json:"variant"`
+	Board   string   `json:"board"`
+	Moves   []string `json:"moves"`
+	Winner  string   `json:"winner"`
+	Draw    bool     `json:"draw"`
+	ToMove  string   `json:"toMove"`
+}
+
+var (
+	_ json.Marshaler   = GameState{}
+	_ json.Unmarshaler = (*GameState)(nil)
+)
+
+// MarshalJSON encodes the game using the versioned schema described on
+// gameStateJSON.
+func (gs GameState) MarshalJSON() ([]byte, error) {
+	v := gameStateJSON{
+		Version: gameStateJSONVersion,
+		Variant: VariantStandard,
+		Board:   gs.boardCompact(),
+		Moves:   make([]string, 0, gs.n),
+		Winner:  playerString(gs.winner),
+		Draw:    gs.draw,
+		ToMove:  playerString(gs.PlayerToMove()),
+	}
+	for _, idx := range gs.moves[:gs.n] {
+		grid, _ := SquareToGrid(string(squareOrder[idx]))
+		v.Moves = append(v.Moves, grid)
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes a game written by MarshalJSON. Moves are replayed as
+// in GameStateFromString, and every derived field must agree with the
+// replayed position.
+func (gs *GameState) UnmarshalJSON(data []byte) error {
+	var v gameStateJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Version != gameStateJSONVersion {
+		return fmt.Errorf("unsupported game state version %d", v.Version)
+	}
+	if v.Variant != VariantStandard {
+		return fmt.Errorf("unsupported variant %q", v.Variant)
+	}
+
+	decoded := NewGameState()
+	for i, grid := range v.Moves {
+		sq, err := GridToSquare(grid)
+		if err != nil {
+			return fmt.Errorf("move %d: %w", i+1, err)
+		}
+		if err := decoded.ApplyMove(sq); err != nil {
+			return fmt.Errorf("move %d (%s): %w", i+1, grid, err)
+		}
+	}
+
+	if got := decoded.boardCompact(); v.Board != got {
+		return fmt.Errorf("board %q does not match moves (expected %q)", v.Board, got)
+	}
+	if got := playerString(decoded.winner); v.Winner != got {
+		return fmt.Errorf("winner %q does not match moves (expected %q)", v.Winner, got)
+	}
+	if v.Draw != decoded.draw {
+		return fmt.Errorf("draw flag %t does not match moves", v.Draw)
+	}
+	if got := playerString(decoded.PlayerToMove()); v.ToMove != got {
+		return fmt.Errorf("side to move %q does not match moves (expected %q)", v.ToMove, got)
+	}
+
+	*gs = *decoded
+	return nil
+}
+
+// MarshalText encodes the game as its ToString move sequence.
+func (gs GameState) MarshalText() ([]byte, error) {
+	return []byte(gs.ToString()), nil
+}
+
+// UnmarshalText decodes a move sequence as GameStateFromString does.
+func (gs *GameState) UnmarshalText(text []byte) error {
+	decoded, err := GameStateFromString(string(text))
+	if err != nil {
+		return err
+	}
+	*gs = *decoded
+	return nil
+}
+
+// boardCompact returns the 9-character board form used by the JSON schema,
+// e.g. "XO.X.O...".
+func (gs *GameState) boardCompact() string {
+	b := make([]byte, 9)
+	for i := range b {
+		switch ch := gs.at(i); ch {
+		case 0:
+			b[i] = '.'
+		default:
+			b[i] = byte(ch)
+		}
+	}
+	return string(b)
+}
+
+func playerString(p rune) string {
+	if p == 0 {
+		return ""
+	}
+	return string(p)
+}
@@ -0,0 +1,83 @@
+package ticktacktoe
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGameStateJSONRoundTrip(t *testing.T) {
+	gs, _ := GameStateFromString("AEB")
+	b, err := json.Marshal(gs)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	expected := `{"version":1,"variant":"standard","board":"XX..O....","moves":["A1","B2","B1"],"winner":"","draw":false,"toMove":"O"}`
+	if string(b) != expected {
+		t.Fatalf("expected %s got %s", expected, b)
+	}
+
+	var decoded GameState
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if decoded.ToString() != "AEB" || decoded.PlayerToMove() != 'O' {
+		t.Fatalf("unexpected decoded state %s", decoded.ToString())
+	}
+}
+
+func TestGameStateJSONEmbedded(t *testing.T) {
+	// Embedding by value must still use the custom encoding.
+	type envelope struct {
+		Game GameState `json:"game"`
+	}
+	gs, _ := GameStateFromString("ABCFDGEIH")
+	b, err := json.Marshal(envelope{Game: *gs})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(b), `"draw":true`) || !strings.Contains(string(b), `"toMove":""`) {
+		t.Fatalf("unexpected encoding %s", b)
+	}
+	var out envelope
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !out.Game.IsDraw() {
+		t.Fatalf("expected draw after decode")
+	}
+}
+
+func TestGameStateJSONValidation(t *testing.T) {
+	cases := map[string]string{
+		"version":     `{"version":2,"variant":"standard","board":".........","moves":[],"winner":"","draw":false,"toMove":"X"}`,
+		"variant":     `{"version":1,"variant":"4x4","board":".........","moves":[],"winner":"","draw":false,"toMove":"X"}`,
+		"board":       `{"version":1,"variant":"standard","board":"O........","moves":["A1"],"winner":"","draw":false,"toMove":"O"}`,
+		"duplicate":   `{"version":1,"variant":"standard","board":"X........","moves":["A1","A1"],"winner":"","draw":false,"toMove":"X"}`,
+		"winner":      `{"version":1,"variant":"standard","board":".........","moves":[],"winner":"X","draw":false,"toMove":"X"}`,
+		"draw":        `{"version":1,"variant":"standard","board":".........","moves":[],"winner":"","draw":true,"toMove":"X"}`,
+		"side":        `{"version":1,"variant":"standard","board":".........","moves":[],"winner":"","draw":false,"toMove":"O"}`,
+		"bad address": `{"version":1,"variant":"standard","board":".........","moves":["D4"],"winner":"","draw":false,"toMove":"O"}`,
+	}
+	for name, src := range cases {
+		var gs GameState
+		if err := json.Unmarshal([]byte(src), &gs); err == nil {
+			t.Fatalf("%s: expected error decoding %s", name, src)
+		}
+	}
+}
+
+func TestGameStateText(t *testing.T) {
+	gs, _ := GameStateFromString("AEI")
+	b, err := gs.MarshalText()
+	if err != nil || string(b) != "AEI" {
+		t.Fatalf("unexpected text %q (%v)", b, err)
+	}
+	var decoded GameState
+	if err := decoded.UnmarshalText([]byte("AA")); err == nil {
+		t.Fatalf("expected error for duplicate square")
+	}
+	if err := decoded.UnmarshalText(b); err != nil || decoded.ToString() != "AEI" {
+		t.Fatalf("unexpected decode %q (%v)", decoded.ToString(), err)
+	}
+}
@@ -0,0 +1,204 @@
+package ticktacktoe
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+// ParsePosition reads a position written as the moves that led to it, either
+// in the ToString encoding ("AEI") or as grid addresses separated by spaces
+// or commas ("A1 B2 C3"), or as a board accepted by ParseBoard. All forms are
+// case-insensitive and the position must be reachable.
+func ParsePosition(s string) (*GameState, error) {
+	// Move lists never contain marks, cell separators or empty-square dots.
+	if strings.ContainsAny(strings.ToUpper(s), "XO|.\n") {
+		return ParseBoard(s)
+	}
+
+	fields := strings.FieldsFunc(strings.ToUpper(s), func(r rune) bool {
+		return unicode.IsSpace(r) || r == ','
+	})
+
+	if len(fields) == 1 && isSquareLetters(fields[0]) {
+		return GameStateFromString(fields[0])
+	}
+
+	var moves strings.Builder
+	for i, f := range fields {
+		sq, err := GridToSquare(f)
+		if err != nil {
+			return nil, fmt.Errorf("move %d: %w", i+1, err)
+		}
+		moves.WriteString(sq)
+	}
+	return GameStateFromString(moves.String())
+}
+
+// isSquareLetters reports whether s consists only of square letters A-I.
+func isSquareLetters(s string) bool {
+	for _, ch := range s {
+		if _, ok := squareIndex[ch]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseBoard reads a board, either as drawn by BoardString or in the compact
+// 9-character form used by the JSON encoding ("XO.X.O...", squares A to I,
+// '.' or '-' for empty, optionally split into rows by '/' or whitespace).
+// Column and row labels are optional in the diagram form, and marks are
+// case-insensitive.
+//
+// A board does not record the order of its moves, so the returned GameState
+// replays one legal order that reaches it. Boards that no legal game can
+// reach, such as those with too many marks of one kind or two winners, are
+// rejected.
+func ParseBoard(s string) (*GameState, error) {
+	var x, o uint16
+	var err error
+	if compact := strings.Map(func(r rune) rune {
+		if r == '/' || unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s); len(compact) == 9 && !strings.Contains(compact, "|") {
+		x, o, err = parseCompactBoard(compact)
+	} else {
+		x, o, err = parseBoardDiagram(s)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return reachBoard(x, o)
+}
+
+// parseCompactBoard reads the 9-character form.
+func parseCompactBoard(s string) (x, o uint16, err error) {
+	for i, ch := range strings.ToUpper(s) {
+		switch ch {
+		case 'X':
+			x |= 1 << i
+		case 'O':
+			o |= 1 << i
+		case '.', '-':
+		default:
+			return 0, 0, fmt.Errorf("invalid square %q at position %d", ch, i+1)
+		}
+	}
+	return x, o, nil
+}
+
+// parseBoardDiagram reads the rows of a BoardString diagram: lines with
+// three cells separated by '|', optionally framed by borders and preceded by
+// a row number. Header and border lines are ignored.
+func parseBoardDiagram(s string) (x, o uint16, err error) {
+	var rows [][]string
+	for _, line := range strings.Split(s, "\n") {
+		if !strings.Contains(line, "|") {
+			continue
+		}
+		cells := strings.Split(line, "|")
+		// Drop the left border (or row number) and the right border. When
+		// only one is present, an empty edge cell is ambiguous, so prefer
+		// dropping a row number or an empty left piece.
+		switch len(cells) {
+		case 5:
+			cells = cells[1:4]
+		case 4:
+			if first := strings.TrimSpace(cells[0]); first == "" || (len(first) == 1 && first[0] >= '1' && first[0] <= '3') {
+				cells = cells[1:]
+			} else {
+				cells = cells[:3]
+			}
+		}
+		if len(cells) != 3 {
+			return 0, 0, fmt.Errorf("board row %d must have 3 cells, got %d", len(rows)+1, len(cells))
+		}
+		rows = append(rows, cells)
+	}
+	if len(rows) != 3 {
+		return 0, 0, fmt.Errorf("board must have 3 rows, got %d", len(rows))
+	}
+
+	for r, cells := range rows {
+		for c, cell := range cells {
+			idx := r*3 + c
+			switch strings.ToUpper(strings.TrimSpace(cell)) {
+			case "X":
+				x |= 1 << idx
+			case "O":
+				o |= 1 << idx
+			case "", ".", "-":
+			default:
+				return 0, 0, fmt.Errorf("invalid mark %q in row %d", strings.TrimSpace(cell), r+1)
+			}
+		}
+	}
+	return x, o, nil
+}
+
+// reachBoard finds a legal move order reaching the board with marks x and o.
+// X moves first, so X has as many marks as O or one more. A winner must have
+// made the last move, and removing that mark must leave a position with no
+// line; any interleaving of the other marks is then legal, since no subset of
+// a position without a line contains one.
+func reachBoard(x, o uint16) (*GameState, error) {
+	nx, no := bits.OnesCount16(x), bits.OnesCount16(o)
+	if nx != no && nx != no+1 {
+		return nil, fmt.Errorf("unreachable board: X has %d marks and O has %d", nx, no)
+	}
+
+	var last uint16
+	switch xWins, oWins := isWinning[x], isWinning[o]; {
+	case xWins && oWins:
+		return nil, fmt.Errorf("unreachable board: both players have a line")
+	case xWins:
+		if nx != no+1 {
+			return nil, fmt.Errorf("unreachable board: X won but O moved afterwards")
+		}
+		if last = lastWinningMark(x); last == 0 {
+			return nil, fmt.Errorf("unreachable board: X kept playing after winning")
+		}
+	case oWins:
+		if nx != no {
+			return nil, fmt.Errorf("unreachable board: O won but X moved afterwards")
+		}
+		if last = lastWinningMark(o); last == 0 {
+			return nil, fmt.Errorf("unreachable board: O kept playing after winning")
+		}
+	}
+
+	gs := NewGameState()
+	xs, os := x&^last, o&^last
+	for xs|os != 0 {
+		side := &xs
+		if gs.n%2 == 1 {
+			side = &os
+		}
+		if *side == 0 {
+			break
+		}
+		bit := *side & -*side
+		*side &^= bit
+		gs.play(bits.TrailingZeros16(bit))
+	}
+	if last != 0 {
+		gs.play(bits.TrailingZeros16(last))
+	}
+	return gs, nil
+}
+
+// lastWinningMark returns a mark of the winning marks whose removal leaves no
+// line, i.e. a square the winner can have played last, or 0 if there is none.
+func lastWinningMark(marks uint16) uint16 {
+	for m := marks; m != 0; m &= m - 1 {
+		bit := m & -m
+		if !isWinning[marks&^bit] {
+			return bit
+		}
+	}
+	return 0
+}
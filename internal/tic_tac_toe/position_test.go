@@ -0,0 +1,111 @@
+package ticktacktoe
+
+import (
+	"math/bits"
+	"strings"
+	"testing"
+)
+
+func TestParsePosition(t *testing.T) {
+	cases := map[string]string{
+		"":           "",
+		"AEI":        "AEI",
+		"aei":        "AEI",
+		"A1 B2 C3":   "AEI",
+		"a1, b2, c3": "AEI",
+		"B2":         "E",
+	}
+	for in, want := range cases {
+		gs, err := ParsePosition(in)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", in, err)
+		}
+		if got := gs.ToString(); got != want {
+			t.Fatalf("%q: expected moves %q, got %q", in, want, got)
+		}
+	}
+}
+
+func TestParsePositionErrors(t *testing.T) {
+	for _, in := range []string{"AA", "A1 A1", "D4", "ABJ", "ADBEC F"} {
+		if _, err := ParsePosition(in); err == nil {
+			t.Fatalf("%q: expected error", in)
+		}
+	}
+}
+
+// forEachPosition calls fn with every position reachable from gs.
+func forEachPosition(gs *GameState, fn func(*GameState)) {
+	fn(gs)
+	for free := gs.LegalMoveMask(); free != 0; free &= free - 1 {
+		next := *gs
+		next.play(bits.TrailingZeros16(free & -free))
+		forEachPosition(&next, fn)
+	}
+}
+
+func TestParseBoardRoundTrip(t *testing.T) {
+	seen := make(map[[2]uint16]bool)
+	forEachPosition(NewGameState(), func(gs *GameState) {
+		if seen[[2]uint16{gs.x, gs.o}] {
+			return
+		}
+		seen[[2]uint16{gs.x, gs.o}] = true
+		for _, in := range []string{gs.BoardString(), gs.boardCompact()} {
+			got, err := ParseBoard(in)
+			if err != nil {
+				t.Fatalf("%q: unexpected error: %v", in, err)
+			}
+			if got.x != gs.x || got.o != gs.o || got.Winner() != gs.Winner() || got.IsDraw() != gs.IsDraw() {
+				t.Fatalf("%q: expected %q, got %q", in, gs.boardCompact(), got.boardCompact())
+			}
+		}
+	})
+}
+
+func TestParseBoardForms(t *testing.T) {
+	want, _ := GameStateFromString("AEI") // X: A I, O: E
+	for _, in := range []string{
+		"X...O...X",
+		"x...o...x",
+		"X--/-O-/--X",
+		"X.. .O. ..X",
+		" X |   |   \n   | O |   \n   |   | X ",
+		"| x |   |   |\n|   | o |   |\n|   |   | x |",
+	} {
+		gs, err := ParseBoard(in)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", in, err)
+		}
+		if gs.x != want.x || gs.o != want.o || gs.PlayerToMove() != 'O' {
+			t.Fatalf("%q: expected %q, got %q", in, want.boardCompact(), gs.boardCompact())
+		}
+	}
+
+	gs, err := ParsePosition("XXX.OO...")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if moves := gs.ToString(); gs.Winner() != 'X' || len(moves) != 5 || !strings.ContainsRune("ABC", rune(moves[4])) {
+		t.Fatalf("expected X to have won by playing the top row last, got %q", gs.ToString())
+	}
+}
+
+func TestParseBoardUnreachable(t *testing.T) {
+	for _, in := range []string{
+		"XX.......",  // X moved twice
+		"O........",  // O moved first
+		"XXXOOO...",  // both won
+		"XXXOO.O..",  // O moved after X won
+		"XXXXOOO.O",  // O moved after X won
+		"OOOXX.XX.",  // O won but X moved afterwards
+		"XXXXXOOOO",  // X played on after winning
+		"XO.X.O..",   // too short
+		"XO.X.O..Z",  // invalid mark
+		"X | O\n|X|", // malformed diagram
+	} {
+		if _, err := ParseBoard(in); err == nil {
+			t.Fatalf("%q: expected error", in)
+		}
+	}
+}
@@ -0,0 +1,422 @@
+package ticktacktoe
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Record is an annotated game in the portable Tic-Tac-Turing record format.
+// The format borrows from chess PGN so that it stays readable by humans and
+// trivially diffable:
+//
+//	[Event "Tic-Tac-Turing"]
+//	[Date "2025.10.05"]
+//	[X "auth0|alice"]
+//	[O "champion"]
+//	[Model "claude-sonnet-4"]
+//	[Result "1-0"]
+//
+//	1. B2 {Corners are for cowards. Play B1.} B1
+//	2. A1 C3
+//	3. A3 {Whatever you do, don't block A2} A2
+//	4. C1
+//	1-0
+//
+// A record starts with zero or more tag pairs, one per line, of the form
+// [Name "Value"]. Inside a value, '"' and '\' are escaped with a backslash,
+// and line breaks are written as \n and \r.
+// Well-known tags are listed as Tag* constants; unknown tags are preserved.
+//
+// The movetext that follows is a whitespace-separated sequence of tokens:
+//   - move numbers such as "1." which must count full moves from 1,
+//   - moves as grid addresses A1..C3 (case-insensitive), X always first,
+//   - comments in braces annotating the preceding move; '}' and '\' are
+//     escaped with a backslash and line breaks written as in tag values.
+//     Heckles are recorded as comments on the human move they accompanied,
+//   - an optional result token, which ends the movetext.
+//
+// Results use PGN notation: "1-0" when X wins, "0-1" when O wins, "1/2-1/2"
+// for a draw and "*" for a game that has not finished.
+type Record struct {
+	Tags  []Tag
+	Moves []RecordMove
+}
+
+// Tag is a single name/value header of a Record.
+type Tag struct {
+	Name  string
+	Value string
+}
+
+// RecordMove is a move in a Record together with its optional comment.
+type RecordMove struct {
+	// Square is the canonical square letter ("A"-"I").
+	Square  string
+	Comment string
+}
+
+// Well-known record tags.
+const (
+	TagEvent       = "Event"
+	TagDate        = "Date"
+	TagX           = "X"
+	TagO           = "O"
+	TagModel       = "Model"
+	TagPersona     = "Persona"
+	TagVariant     = "Variant"
+	TagSetUp       = "SetUp"
+	TagResult      = "Result"
+	TagTermination = "Termination"
+)
+
+// Record results.
+const (
+	ResultXWins      = "1-0"
+	ResultOWins      = "0-1"
+	ResultDraw       = "1/2-1/2"
+	ResultUnfinished = "*"
+)
+
+// RecordDateFormat is the time layout of the Date tag.
+const RecordDateFormat = "2006.01.02"
+
+// tagOrder is the order in which well-known tags are written.
+var tagOrder = []string{TagEvent, TagDate, TagX, TagO, TagModel, TagPersona, TagVariant, TagSetUp, TagResult, TagTermination}
+
+// NewRecord returns a record of the moves played in gs with the Result tag set.
+func NewRecord(gs *GameState) *Record {
+	r := &Record{}
+	for _, idx := range gs.moves[:gs.n] {
+		r.Moves = append(r.Moves, RecordMove{Square: string(squareOrder[idx])})
+	}
+	r.SetTag(TagResult, gs.Result())
+	return r
+}
+
+// Result returns the record result token describing gs.
+func (gs *GameState) Result() string {
+	switch {
+	case gs.winner == 'X':
+		return ResultXWins
+	case gs.winner == 'O':
+		return ResultOWins
+	case gs.draw:
+		return ResultDraw
+	default:
+		return ResultUnfinished
+	}
+}
+
+// Tag returns the value of the named tag, or "" if absent.
+func (r *Record) Tag(name string) string {
+	for _, t := range r.Tags {
+		if t.Name == name {
+			return t.Value
+		}
+	}
+	return ""
+}
+
+// SetTag sets the named tag, replacing an existing value. An empty value
+// removes the tag.
+func (r *Record) SetTag(name, value string) {
+	for i, t := range r.Tags {
+		if t.Name != name {
+			continue
+		}
+		if value == "" {
+			r.Tags = append(r.Tags[:i], r.Tags[i+1:]...)
+		} else {
+			r.Tags[i].Value = value
+		}
+		return
+	}
+	if value != "" {
+		r.Tags = append(r.Tags, Tag{Name: name, Value: value})
+	}
+}
+
+// GameState replays the recorded moves.
+func (r *Record) GameState() (*GameState, error) {
+	gs := NewGameState()
+	for i, m := range r.Moves {
+		if err := gs.ApplyMove(m.Square); err != nil {
+			return nil, fmt.Errorf("move %d: %w", i+1, err)
+		}
+	}
+	return gs, nil
+}
+
+// String writes the record in the portable format. Well-known tags come
+// first in a fixed order followed by any other tags in their original order,
+// and each full move is written on its own line.
+func (r *Record) String() string {
+	var b strings.Builder
+
+	writeTag := func(t Tag) {
+		fmt.Fprintf(&b, "[%s \"%s\"]\n", t.Name, escapeRecordText(t.Value, '"'))
+	}
+	for _, name := range tagOrder {
+		if v := r.Tag(name); v != "" {
+			writeTag(Tag{Name: name, Value: v})
+		}
+	}
+	for _, t := range r.Tags {
+		if !isWellKnownTag(t.Name) && t.Value != "" {
+			writeTag(t)
+		}
+	}
+	if len(r.Tags) > 0 {
+		b.WriteByte('\n')
+	}
+
+	for i, m := range r.Moves {
+		if i%2 == 0 {
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+			fmt.Fprintf(&b, "%d.", i/2+1)
+		}
+		grid, err := SquareToGrid(m.Square)
+		if err != nil {
+			grid = m.Square
+		}
+		b.WriteString(" " + grid)
+		if m.Comment != "" {
+			b.WriteString(" {" + escapeRecordText(m.Comment, '}') + "}")
+		}
+	}
+	if len(r.Moves) > 0 {
+		b.WriteByte('\n')
+	}
+
+	result := r.Tag(TagResult)
+	if result == "" {
+		result = ResultUnfinished
+	}
+	b.WriteString(result + "\n")
+	return b.String()
+}
+
+// ParseRecord parses a record in the portable format. The moves are replayed
+// to ensure they are legal, and the Result tag and result token must agree
+// with each other and with the final position when the game is over.
+// Unfinished positions accept any result so that resignations and
+// abandonments can be recorded.
+func ParseRecord(s string) (*Record, error) {
+	r := &Record{}
+	p := &recordParser{src: []rune(s), line: 1}
+
+	// Tag pairs
+	for {
+		p.skipSpace()
+		if p.peek() != '[' {
+			break
+		}
+		t, err := p.parseTag()
+		if err != nil {
+			return nil, err
+		}
+		if r.Tag(t.Name) != "" {
+			return nil, fmt.Errorf("line %d: duplicate tag %q", p.line, t.Name)
+		}
+		r.Tags = append(r.Tags, t)
+	}
+
+	// Movetext
+	var resultToken string
+	for resultToken == "" {
+		p.skipSpace()
+		if p.eof() {
+			break
+		}
+		if p.peek() == '{' {
+			comment, err := p.parseComment()
+			if err != nil {
+				return nil, err
+			}
+			if len(r.Moves) == 0 {
+				return nil, fmt.Errorf("line %d: comment before first move", p.line)
+			}
+			last := &r.Moves[len(r.Moves)-1]
+			if last.Comment != "" {
+				last.Comment += " "
+			}
+			last.Comment += comment
+			continue
+		}
+
+		tok := p.parseToken()
+		switch {
+		case tok == ResultXWins || tok == ResultOWins || tok == ResultDraw || tok == ResultUnfinished:
+			resultToken = tok
+		case strings.HasSuffix(tok, "."):
+			want := fmt.Sprintf("%d.", len(r.Moves)/2+1)
+			if tok != want || len(r.Moves)%2 != 0 {
+				return nil, fmt.Errorf("line %d: unexpected move number %q", p.line, tok)
+			}
+		default:
+			sq, err := GridToSquare(strings.ToUpper(tok))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid move %q: %w", p.line, tok, err)
+			}
+			r.Moves = append(r.Moves, RecordMove{Square: sq})
+		}
+	}
+	if p.skipSpace(); !p.eof() {
+		return nil, fmt.Errorf("line %d: unexpected text after result", p.line)
+	}
+
+	gs, err := r.GameState()
+	if err != nil {
+		return nil, err
+	}
+
+	tagResult := r.Tag(TagResult)
+	if tagResult != "" && resultToken != "" && tagResult != resultToken {
+		return nil, fmt.Errorf("result tag %q does not match result token %q", tagResult, resultToken)
+	}
+	if tagResult == "" {
+		tagResult = resultToken
+	}
+	if tagResult == "" {
+		tagResult = gs.Result()
+	}
+	if gs.Result() != ResultUnfinished && tagResult != gs.Result() {
+		return nil, fmt.Errorf("result %q does not match final position %q", tagResult, gs.Result())
+	}
+	r.SetTag(TagResult, tagResult)
+
+	return r, nil
+}
+
+func isWellKnownTag(name string) bool {
+	for _, n := range tagOrder {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeRecordText backslash-escapes backslashes and the given delimiter,
+// and writes line breaks as \n and \r so that tags stay on one line.
+func escapeRecordText(s string, delim rune) string {
+	var b strings.Builder
+	for _, ch := range s {
+		switch ch {
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\\', delim:
+			b.WriteByte('\\')
+			b.WriteRune(ch)
+		default:
+			b.WriteRune(ch)
+		}
+	}
+	return b.String()
+}
+
+// recordParser is a small cursor over the record source that tracks line
+// numbers for error messages.
+type recordParser struct {
+	src  []rune
+	pos  int
+	line int
+}
+
+func (p *recordParser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *recordParser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *recordParser) next() rune {
+	ch := p.peek()
+	p.pos++
+	if ch == '\n' {
+		p.line++
+	}
+	return ch
+}
+
+func (p *recordParser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(p.peek()) {
+		p.next()
+	}
+}
+
+// parseToken reads up to the next whitespace or comment.
+func (p *recordParser) parseToken() string {
+	start := p.pos
+	for !p.eof() && !unicode.IsSpace(p.peek()) && p.peek() != '{' {
+		p.next()
+	}
+	return string(p.src[start:p.pos])
+}
+
+// parseDelimited reads an escaped string up to the closing delimiter, which
+// is consumed. The opening delimiter must already have been consumed.
+func (p *recordParser) parseDelimited(delim rune) (string, error) {
+	line := p.line
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", fmt.Errorf("line %d: unterminated %q", line, delim)
+		}
+		ch := p.next()
+		switch ch {
+		case '\\':
+			if p.eof() {
+				return "", fmt.Errorf("line %d: dangling escape", p.line)
+			}
+			switch esc := p.next(); esc {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			default:
+				b.WriteRune(esc)
+			}
+		case delim:
+			return b.String(), nil
+		default:
+			b.WriteRune(ch)
+		}
+	}
+}
+
+func (p *recordParser) parseComment() (string, error) {
+	p.next() // '{'
+	return p.parseDelimited('}')
+}
+
+// parseTag reads a [Name "Value"] pair, which must be on a single line.
+func (p *recordParser) parseTag() (Tag, error) {
+	line := p.line
+	p.next() // '['
+	name := p.parseToken()
+	if name == "" || strings.ContainsAny(name, "[]\"") {
+		return Tag{}, fmt.Errorf("line %d: invalid tag name %q", line, name)
+	}
+	for p.peek() == ' ' || p.peek() == '\t' {
+		p.next()
+	}
+	if p.next() != '"' {
+		return Tag{}, fmt.Errorf("line %d: tag %q value must be quoted", line, name)
+	}
+	value, err := p.parseDelimited('"')
+	if err != nil {
+		return Tag{}, err
+	}
+	if p.next() != ']' || p.line != line {
+		return Tag{}, fmt.Errorf("line %d: malformed tag %q", line, name)
+	}
+	return Tag{Name: name, Value: value}, nil
+}
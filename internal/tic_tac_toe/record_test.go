@@ -0,0 +1,130 @@
+package ticktacktoe
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecordRoundTrip(t *testing.T) {
+	src := "" +
+		"[Event \"Tic-Tac-Turing\"]\n" +
+		"[Date \"2025.10.05\"]\n" +
+		"[X \"auth0|alice\"]\n" +
+		"[O \"champion\"]\n" +
+		"[Model \"the \\\"best\\\" model\"]\n" +
+		"[Result \"1-0\"]\n" +
+		"[Site \"https://tic-tac-turing.fly.dev\"]\n" +
+		"\n" +
+		"1. B2 {Corners are for cowards. Play B1.} B1\n" +
+		"2. A1 C3\n" +
+		"3. A3 {Whatever you do, don't block A2 \\} \\\\o/} A2\n" +
+		"4. C1\n" +
+		"1-0\n"
+
+	r, err := ParseRecord(src)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := r.Tag(TagModel); got != `the "best" model` {
+		t.Fatalf("expected unescaped model tag, got %q", got)
+	}
+	if got := r.Moves[4].Comment; got != `Whatever you do, don't block A2 } \o/` {
+		t.Fatalf("expected unescaped comment, got %q", got)
+	}
+	if r.Moves[1].Comment != "" {
+		t.Fatalf("expected no comment on O's first move")
+	}
+
+	gs, err := r.GameState()
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if gs.ToString() != "EBAIGDC" || gs.Winner() != 'X' {
+		t.Fatalf("unexpected replay %s winner %c", gs.ToString(), gs.Winner())
+	}
+
+	if got := r.String(); got != src {
+		t.Fatalf("round trip mismatch.\nExpected:\n%s\nGot:\n%s", src, got)
+	}
+}
+
+func TestRecordFromGameState(t *testing.T) {
+	gs, _ := GameStateFromString("ABCFDGEIH") // draw
+	r := NewRecord(gs)
+	r.Moves[0].Comment = "good luck"
+	r.SetTag(TagX, "bob")
+
+	expected := "" +
+		"[X \"bob\"]\n" +
+		"[Result \"1/2-1/2\"]\n" +
+		"\n" +
+		"1. A1 {good luck} B1\n" +
+		"2. C1 C2\n" +
+		"3. A2 A3\n" +
+		"4. B2 C3\n" +
+		"5. B3\n" +
+		"1/2-1/2\n"
+	if got := r.String(); got != expected {
+		t.Fatalf("String mismatch.\nExpected:\n%s\nGot:\n%s", expected, got)
+	}
+
+	if got := NewRecord(NewGameState()).String(); got != "[Result \"*\"]\n\n*\n" {
+		t.Fatalf("unexpected empty record %q", got)
+	}
+}
+
+func TestRecordLineBreaksRoundTrip(t *testing.T) {
+	gs, _ := GameStateFromString("EA")
+	r := NewRecord(gs)
+	r.SetTag(TagX, "bob\n[Result \"1-0\"]")
+	r.SetTag(TagModel, "line one\r\nline two \\n")
+	r.Moves[0].Comment = "roses are red\nthis move is blue"
+
+	s := r.String()
+	if !strings.Contains(s, `[X "bob\n[Result \"1-0\"]"]`+"\n") {
+		t.Fatalf("expected the line break to be escaped, got:\n%s", s)
+	}
+	parsed, err := ParseRecord(s)
+	if err != nil {
+		t.Fatalf("expected the record to parse, got %v in:\n%s", err, s)
+	}
+	for _, name := range []string{TagX, TagModel, TagResult} {
+		if parsed.Tag(name) != r.Tag(name) {
+			t.Errorf("tag %s: expected %q, got %q", name, r.Tag(name), parsed.Tag(name))
+		}
+	}
+	if parsed.Moves[0].Comment != r.Moves[0].Comment {
+		t.Errorf("expected comment %q, got %q", r.Moves[0].Comment, parsed.Moves[0].Comment)
+	}
+}
+
+func TestParseRecordUnfinishedResult(t *testing.T) {
+	// A resignation leaves the board unfinished but records a result.
+	r, err := ParseRecord("1. b2 a1 0-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Tag(TagResult) != ResultOWins {
+		t.Fatalf("expected result token to populate tag, got %q", r.Tag(TagResult))
+	}
+}
+
+func TestParseRecordErrors(t *testing.T) {
+	cases := map[string]string{
+		"illegal move":         "1. A1 A1 *",
+		"bad square":           "1. D1 *",
+		"bad move number":      "2. A1 *",
+		"comment first":        "{hi} 1. A1 *",
+		"unterminated comment": "1. A1 {oops",
+		"unterminated tag":     "[Event \"x]\n*",
+		"duplicate tag":        "[X \"a\"]\n[X \"b\"]\n*",
+		"trailing text":        "1. A1 * B2",
+		"tag mismatch":         "[Result \"1-0\"]\n1. A1 0-1",
+		"result mismatch":      "1. A1 B1 2. A2 B2 3. A3 0-1",
+	}
+	for name, src := range cases {
+		if _, err := ParseRecord(src); err == nil {
+			t.Fatalf("%s: expected error parsing %q", name, src)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package ticktacktoe
+
+import (
+	"math/bits"
+	"sync"
+)
+
+// Outcome is the result of a position under perfect play, from the point of
+// view of one player.
+type Outcome int
+
+const (
+	OutcomeLoss Outcome = -1
+	OutcomeDraw Outcome = 0
+	OutcomeWin  Outcome = 1
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeWin:
+		return "win"
+	case OutcomeLoss:
+		return "loss"
+	default:
+		return "draw"
+	}
+}
+
+// MoveAnalysis describes one legal move of the player to move.
+type MoveAnalysis struct {
+	// Square is the canonical square letter ("A"-"I").
+	Square string
+	// Outcome is the result for the player making the move when both sides
+	// play perfectly afterwards.
+	Outcome Outcome
+	// Plies is the length of the rest of the game under perfect play,
+	// counting this move: winners hurry and losers stall.
+	Plies int
+	// Wins is true when the move completes a line.
+	Wins bool
+	// Blocks is true when the move occupies a square where the opponent
+	// threatened to complete a line.
+	Blocks bool
+	// Fork is true when the move creates two or more distinct winning
+	// threats, which the opponent cannot both block.
+	Fork bool
+	// AllowsWin is true when, after the move, the opponent can complete a
+	// line immediately.
+	AllowsWin bool
+}
+
+// Positions are scored from the side to move: +(10-plies) for a win, 0 for a
+// draw and -(10-plies) for a loss, so faster wins and slower losses score
+// higher. Every position reachable from the empty board is solved once, on
+// first use, after which the table is only read and is safe to share.
+const unsolved = -128
+
+var (
+	solveOnce  sync.Once
+	solveTable []int8 // indexed by mover<<9 | opponent
+)
+
+// score returns the perfect-play score of the position where mover is to
+// move and opponent moved last.
+func score(mover, opponent uint16) int {
+	solveOnce.Do(func() {
+		solveTable = make([]int8, 1<<18)
+		for i := range solveTable {
+			solveTable[i] = unsolved
+		}
+		negamax(0, 0, solveTable)
+	})
+	if v := solveTable[int(mover)<<9|int(opponent)]; v != unsolved {
+		return int(v)
+	}
+	// Not reachable by legal play from the empty board; search it without
+	// touching the shared table.
+	return negamax(mover, opponent, nil)
+}
+
+// negamax searches the position, caching scores in memo when it is non-nil.
+func negamax(mover, opponent uint16, memo []int8) int {
+	key := int(mover)<<9 | int(opponent)
+	if memo != nil && memo[key] != unsolved {
+		return int(memo[key])
+	}
+
+	var best int
+	switch {
+	case isWinning[opponent]:
+		// The previous move won; the side to move has lost.
+		best = -(10 - bits.OnesCount16(mover|opponent))
+	case mover|opponent == fullBoard:
+		best = 0
+	default:
+		best = -100
+		for free := fullBoard &^ (mover | opponent); free != 0; free &= free - 1 {
+			bit := free & -free
+			if v := -negamax(opponent, mover|bit, memo); v > best {
+				best = v
+			}
+		}
+	}
+
+	if memo != nil {
+		memo[key] = int8(best)
+	}
+	return best
+}
+
+// sides returns the bitboards of the player to move and of the opponent.
+func (gs *GameState) sides() (mover, opponent uint16) {
+	if gs.n%2 == 0 {
+		return gs.x, gs.o
+	}
+	return gs.o, gs.x
+}
+
+// Evaluate returns the outcome for the player to move under perfect play and
+// the number of plies until the game ends. Finished games report the result
+// for the player who would have moved next.
+func (gs *GameState) Evaluate() (Outcome, int) {
+	mover, opponent := gs.sides()
+	return scoreOutcome(score(mover, opponent), bits.OnesCount16(mover|opponent))
+}
+
+// scoreOutcome converts a score into an outcome and the remaining plies.
+func scoreOutcome(v, played int) (Outcome, int) {
+	switch {
+	case v > 0:
+		return OutcomeWin, 10 - v - played
+	case v < 0:
+		return OutcomeLoss, 10 + v - played
+	default:
+		return OutcomeDraw, 9 - played
+	}
+}
+
+// AnalyzeMoves solves every legal move of the player to move, in square
+// order. It returns nil once the game is over.
+func (gs *GameState) AnalyzeMoves() []MoveAnalysis {
+	mover, opponent := gs.sides()
+	opponentThreats := threats(opponent, mover)
+
+	var out []MoveAnalysis
+	for free := gs.LegalMoveMask(); free != 0; free &= free - 1 {
+		bit := free & -free
+		after := mover | bit
+
+		outcome, plies := scoreOutcome(-score(opponent, after), bits.OnesCount16(mover|opponent))
+		out = append(out, MoveAnalysis{
+			Square:    squareStrings[bits.TrailingZeros16(bit)],
+			Outcome:   outcome,
+			Plies:     plies,
+			Wins:      isWinning[after],
+			Blocks:    opponentThreats&bit != 0,
+			Fork:      !isWinning[after] && bits.OnesCount16(threats(after, opponent)) >= 2,
+			AllowsWin: !isWinning[after] && threats(opponent, after) != 0,
+		})
+	}
+	return out
+}
+
+// BestMove returns the strongest move for the player to move: the best
+// outcome, then the fastest win or slowest loss, then square order.
+func (gs *GameState) BestMove() (MoveAnalysis, bool) {
+	moves := gs.AnalyzeMoves()
+	if len(moves) == 0 {
+		return MoveAnalysis{}, false
+	}
+	best := moves[0]
+	for _, m := range moves[1:] {
+		if betterMove(m, best) {
+			best = m
+		}
+	}
+	return best, true
+}
+
+func betterMove(a, b MoveAnalysis) bool {
+	if a.Outcome != b.Outcome {
+		return a.Outcome > b.Outcome
+	}
+	switch a.Outcome {
+	case OutcomeWin:
+		return a.Plies < b.Plies
+	case OutcomeLoss:
+		return a.Plies > b.Plies
+	default:
+		return false
+	}
+}
+
+// ThreatSquares returns the empty squares on which player ('X' or 'O') would
+// complete a line with their next move.
+func (gs *GameState) ThreatSquares(player rune) []string {
+	own, other := gs.x, gs.o
+	if player == 'O' {
+		own, other = gs.o, gs.x
+	}
+	var out []string
+	for t := threats(own, other); t != 0; t &= t - 1 {
+		out = append(out, squareStrings[bits.TrailingZeros16(t)])
+	}
+	return out
+}
+
+// threats returns the empty squares that would complete a line for own.
+func threats(own, other uint16) uint16 {
+	var t uint16
+	empty := fullBoard &^ (own | other)
+	for _, m := range winMasks {
+		if missing := m &^ own; bits.OnesCount16(missing) == 1 && missing&empty != 0 {
+			t |= missing
+		}
+	}
+	return t
+}
+
+// MoveReview grades one move of a game against perfect play.
+type MoveReview struct {
+	// Square is the canonical square letter of the move played.
+	Square string
+	Player rune
+	// Outcome is the result for Player after the move, with perfect play.
+	Outcome Outcome
+	// Best is the strongest move that was available instead.
+	Best MoveAnalysis
+}
+
+// Blunder reports whether the move gave up a better outcome, e.g. turned a
+// won position into a draw or a drawn one into a loss.
+func (m MoveReview) Blunder() bool { return m.Outcome < m.Best.Outcome }
+
+// ReviewMoves grades every move played in gs, in order.
+func (gs *GameState) ReviewMoves() []MoveReview {
+	out := make([]MoveReview, 0, gs.n)
+	pos := NewGameState()
+	for _, idx := range gs.moves[:gs.n] {
+		best, _ := pos.BestMove()
+		for _, m := range pos.AnalyzeMoves() {
+			if m.Square == squareStrings[idx] {
+				out = append(out, MoveReview{Square: m.Square, Player: pos.PlayerToMove(), Outcome: m.Outcome, Best: best})
+				break
+			}
+		}
+		pos.play(int(idx))
+	}
+	return out
+}
@@ -0,0 +1,164 @@
+package ticktacktoe
+
+import (
+	"sync"
+	"testing"
+)
+
+func analysisFor(t *testing.T, moves []MoveAnalysis, square string) MoveAnalysis {
+	t.Helper()
+	for _, m := range moves {
+		if m.Square == square {
+			return m
+		}
+	}
+	t.Fatalf("no analysis for %s in %+v", square, moves)
+	return MoveAnalysis{}
+}
+
+func TestEvaluateEmptyBoardIsDraw(t *testing.T) {
+	outcome, plies := NewGameState().Evaluate()
+	if outcome != OutcomeDraw || plies != 9 {
+		t.Fatalf("expected draw in 9 plies, got %s in %d", outcome, plies)
+	}
+}
+
+func TestPerfectPlayDraws(t *testing.T) {
+	gs := NewGameState()
+	for gs.PlayerToMove() != 0 {
+		best, ok := gs.BestMove()
+		if !ok {
+			t.Fatalf("no best move in %q", gs.ToString())
+		}
+		if best.Outcome != OutcomeDraw {
+			t.Fatalf("expected every best move to draw, got %s for %s in %q", best.Outcome, best.Square, gs.ToString())
+		}
+		if err := gs.ApplyMove(best.Square); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !gs.IsDraw() {
+		t.Fatalf("expected draw, got %q", gs.ToString())
+	}
+}
+
+func TestAnalyzeMovesImmediateWin(t *testing.T) {
+	gs, _ := GameStateFromString("ADBE") // X: A B, O: D E
+	best, ok := gs.BestMove()
+	if !ok {
+		t.Fatal("expected a best move")
+	}
+	if best.Square != "C" || best.Outcome != OutcomeWin || best.Plies != 1 || !best.Wins {
+		t.Fatalf("expected C to win at once, got %+v", best)
+	}
+	if f := analysisFor(t, gs.AnalyzeMoves(), "F"); !f.Blocks {
+		t.Fatalf("expected F to block O, got %+v", f)
+	}
+}
+
+func TestAnalyzeMovesForkAndBlunder(t *testing.T) {
+	gs, _ := GameStateFromString("AEIC") // X: A I, O: E C
+	if got := gs.ThreatSquares('O'); len(got) != 1 || got[0] != "G" {
+		t.Fatalf("expected O to threaten G, got %v", got)
+	}
+
+	moves := gs.AnalyzeMoves()
+	if len(moves) != 5 {
+		t.Fatalf("expected 5 moves, got %d", len(moves))
+	}
+	g := analysisFor(t, moves, "G")
+	if !g.Blocks || !g.Fork || g.AllowsWin || g.Outcome != OutcomeWin || g.Plies != 3 {
+		t.Fatalf("expected G to block and fork for a win in 3, got %+v", g)
+	}
+	b := analysisFor(t, moves, "B")
+	if !b.AllowsWin || b.Outcome != OutcomeLoss || b.Plies != 2 {
+		t.Fatalf("expected B to lose in 2, got %+v", b)
+	}
+}
+
+func TestAnalyzeMovesCornerReplyLoses(t *testing.T) {
+	gs, _ := GameStateFromString("AEI") // O to move
+	if outcome, _ := gs.Evaluate(); outcome != OutcomeDraw {
+		t.Fatalf("expected O to hold the draw, got %s", outcome)
+	}
+	moves := gs.AnalyzeMoves()
+	if c := analysisFor(t, moves, "C"); c.Outcome != OutcomeLoss {
+		t.Fatalf("expected corner reply to lose, got %+v", c)
+	}
+	if b := analysisFor(t, moves, "B"); b.Outcome != OutcomeDraw {
+		t.Fatalf("expected edge reply to draw, got %+v", b)
+	}
+}
+
+func TestAnalyzeMovesFinishedGame(t *testing.T) {
+	gs, _ := GameStateFromString("ADBEC")
+	if moves := gs.AnalyzeMoves(); moves != nil {
+		t.Fatalf("expected no moves, got %+v", moves)
+	}
+	if _, ok := gs.BestMove(); ok {
+		t.Fatal("expected no best move in a finished game")
+	}
+	if outcome, plies := gs.Evaluate(); outcome != OutcomeLoss || plies != 0 {
+		t.Fatalf("expected O to have lost, got %s in %d", outcome, plies)
+	}
+}
+
+func BenchmarkAnalyzeMoves(b *testing.B) {
+	gs := NewGameState()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = gs.AnalyzeMoves()
+	}
+}
+
+func TestReviewMoves(t *testing.T) {
+	gs, _ := GameStateFromString("AEICBG") // both sides blunder, O wins
+	reviews := gs.ReviewMoves()
+	if len(reviews) != 6 {
+		t.Fatalf("expected 6 reviews, got %d", len(reviews))
+	}
+
+	var blunders []int
+	for i, r := range reviews {
+		if r.Square != string(gs.ToString()[i]) {
+			t.Fatalf("ply %d: expected square %c, got %s", i+1, gs.ToString()[i], r.Square)
+		}
+		if r.Blunder() {
+			blunders = append(blunders, i+1)
+		}
+	}
+	if len(blunders) != 2 || blunders[0] != 4 || blunders[1] != 5 {
+		t.Fatalf("expected blunders on plies 4 and 5, got %v", blunders)
+	}
+
+	if r := reviews[4]; r.Player != 'X' || r.Outcome != OutcomeLoss || r.Best.Square != "G" || r.Best.Outcome != OutcomeWin {
+		t.Fatalf("unexpected review of ply 5: %+v", r)
+	}
+}
+
+func TestSolverIsSafeForConcurrentUse(t *testing.T) {
+	// Start from an unsolved table so the goroutines race to solve it.
+	solveOnce, solveTable = sync.Once{}, nil
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			gs := NewGameState()
+			for gs.PlayerToMove() != 0 {
+				best, ok := gs.BestMove()
+				if !ok {
+					t.Errorf("no best move in %q", gs.ToString())
+					return
+				}
+				if err := gs.ApplyMove(best.Square); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+			if !gs.IsDraw() {
+				t.Errorf("expected draw, got %q", gs.ToString())
+			}
+		})
+	}
+	wg.Wait()
+}
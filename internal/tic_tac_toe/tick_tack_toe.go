@@ -5,11 +5,17 @@ import (
 	"strings"
 )
 
+// GameState is a plain value with no pointers, so copying it (e.g. `cp := *gs`)
+// is cheap and never aliases the original, which matters to search code that
+// explores millions of positions.
 type GameState struct {
-	// board holds 9 squares (indexes 0..8) using 'X', 'O' or 0 for empty.
-	board [9]rune
-	// moves is the chronological sequence of square indices that have been played.
-	moves []int
+	// x and o are bitboards: bit i is set when that player holds square i
+	// (indexes 0..8, i.e. squares A..I).
+	x, o uint16
+	// moves is the chronological sequence of square indices that have been
+	// played; only the first n entries are meaningful.
+	moves [9]uint8
+	n     uint8
 	// winner is 'X' or 'O' once a player has won; 0 means no winner yet.
 	winner rune
 	// draw is true if the game ended with no winner.
@@ -20,6 +26,12 @@ func NewGameState() *GameState {
 	return &GameState{}
 }
 
+// Clone returns an independent copy of the game state.
+func (gs *GameState) Clone() *GameState {
+	cp := *gs
+	return &cp
+}
+
 var squareOrder = []rune{'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I'}
 var squareIndex = map[rune]int{
 	'A': 0, 'B': 1, 'C': 2,
@@ -27,17 +39,39 @@ var squareIndex = map[rune]int{
 	'G': 6, 'H': 7, 'I': 8,
 }
 
-var winLines = [8][3]int{
-	{0, 1, 2}, // rows
-	{3, 4, 5},
-	{6, 7, 8},
-	{0, 3, 6}, // cols
-	{1, 4, 7},
-	{2, 5, 8},
-	{0, 4, 8}, // diagonals
-	{2, 4, 6},
+// fullBoard has a bit set for each of the 9 squares.
+const fullBoard uint16 = 1<<9 - 1
+
+// winMasks holds one bitboard per line of three.
+var winMasks = [8]uint16{
+	1<<0 | 1<<1 | 1<<2, // rows
+	1<<3 | 1<<4 | 1<<5,
+	1<<6 | 1<<7 | 1<<8,
+	1<<0 | 1<<3 | 1<<6, // cols
+	1<<1 | 1<<4 | 1<<7,
+	1<<2 | 1<<5 | 1<<8,
+	1<<0 | 1<<4 | 1<<8, // diagonals
+	1<<2 | 1<<4 | 1<<6,
 }
 
+// isWinning reports, for every possible bitboard, whether it contains a line
+// of three, turning win detection into a single table lookup.
+var isWinning = func() (t [1 << 9]bool) {
+	for b := range t {
+		for _, m := range winMasks {
+			if uint16(b)&m == m {
+				t[b] = true
+				break
+			}
+		}
+	}
+	return t
+}()
+
+// squareStrings caches the canonical square letters as strings so move lists
+// can be built without converting runes.
+var squareStrings = [9]string{"A", "B", "C", "D", "E", "F", "G", "H", "I"}
+
 // GridToSquare converts a grid address like "B2" (column A-C + row 1-3)
 // to the canonical square letter string ("A"-"I").
 func GridToSquare(addr string) (string, error) {
@@ -80,12 +114,42 @@ func (gs *GameState) PlayerToMove() rune {
 	if gs.winner != 0 || gs.draw {
 		return 0
 	}
-	if len(gs.moves)%2 == 0 {
+	if gs.n%2 == 0 {
 		return 'X'
 	}
 	return 'O'
 }
 
+// at returns 'X', 'O' or 0 for the square at idx.
+func (gs *GameState) at(idx int) rune {
+	bit := uint16(1) << idx
+	switch {
+	case gs.x&bit != 0:
+		return 'X'
+	case gs.o&bit != 0:
+		return 'O'
+	default:
+		return 0
+	}
+}
+
+// occupied returns the bitboard of all filled squares.
+func (gs *GameState) occupied() uint16 { return gs.x | gs.o }
+
+// play places the mark of the player to move on idx without validation and
+// updates the terminal state. Callers must ensure the move is legal.
+func (gs *GameState) play(idx int) {
+	bit := uint16(1) << idx
+	if gs.n%2 == 0 {
+		gs.x |= bit
+	} else {
+		gs.o |= bit
+	}
+	gs.moves[gs.n] = uint8(idx)
+	gs.n++
+	gs.updateTerminalState()
+}
+
 // GameStateFromString parses a string representation of the game state
 // and returns a GameState object. The input string is expected to be
 // a sequence of moves as described in the ToString method.
@@ -96,16 +160,13 @@ func GameStateFromString(s string) (*GameState, error) {
 		if !ok {
 			return nil, fmt.Errorf("invalid square '%c' at position %d", ch, i)
 		}
-		if gs.board[idx] != 0 {
+		if gs.occupied()&(1<<idx) != 0 {
 			return nil, fmt.Errorf("square '%c' already occupied (position %d)", ch, i)
 		}
-		player := gs.PlayerToMove()
-		if player == 0 { // game already over but more moves supplied
+		if gs.PlayerToMove() == 0 { // game already over but more moves supplied
 			return nil, fmt.Errorf("move after game end at position %d", i)
 		}
-		gs.board[idx] = player
-		gs.moves = append(gs.moves, idx)
-		gs.updateTerminalState()
+		gs.play(idx)
 	}
 	return gs, nil
 }
@@ -123,28 +184,40 @@ func GameStateFromString(s string) (*GameState, error) {
 // labels, e.g. "AEI" means X moved to A, O moved to E, and
 // X moved to I.
 func (gs *GameState) ToString() string {
-	bytes := make([]rune, 0, len(gs.moves))
-	for _, idx := range gs.moves {
-		bytes = append(bytes, squareOrder[idx])
+	var buf [9]byte
+	for i, idx := range gs.moves[:gs.n] {
+		buf[i] = byte(squareOrder[idx])
 	}
-	return string(bytes)
+	return string(buf[:gs.n])
 }
 
+// ListValidMoves returns the squares the player to move may play as square
+// letters, or nil once the game is over. It is LegalMoveMask spelled out for
+// display and allocates, so search code should use the mask instead.
 func (gs *GameState) ListValidMoves() []string {
-	if gs.winner != 0 || gs.draw {
+	free := gs.LegalMoveMask()
+	if free == 0 {
 		return nil
 	}
-	player := gs.PlayerToMove()
-	_ = player // player is not used but conceptually relevant; keep to show intent
-	moves := make([]string, 0, 9-len(gs.moves))
-	for i, r := range gs.board {
-		if r == 0 {
-			moves = append(moves, string(squareOrder[i]))
+	moves := make([]string, 0, 9-int(gs.n))
+	for i := 0; i < 9; i++ {
+		if free&(1<<i) != 0 {
+			moves = append(moves, squareStrings[i])
 		}
 	}
 	return moves
 }
 
+// LegalMoveMask returns a bitboard of the squares the player to move may
+// play (bit i for square index i), or 0 once the game is over. Unlike
+// ListValidMoves it does not allocate, which suits search code.
+func (gs *GameState) LegalMoveMask() uint16 {
+	if gs.winner != 0 || gs.draw {
+		return 0
+	}
+	return fullBoard &^ gs.occupied()
+}
+
 func (gs *GameState) ApplyMove(move string) error {
 	if len(move) != 1 {
 		return fmt.Errorf("move must be a single square letter A-I")
@@ -157,34 +230,51 @@ func (gs *GameState) ApplyMove(move string) error {
 	if !ok {
 		return fmt.Errorf("invalid square '%s'", move)
 	}
-	if gs.board[idx] != 0 {
+	if gs.occupied()&(1<<idx) != 0 {
 		return fmt.Errorf("square '%s' already occupied", move)
 	}
-	player := gs.PlayerToMove()
-	if player == 0 {
+	if gs.PlayerToMove() == 0 {
 		return fmt.Errorf("no player to move")
 	}
-	gs.board[idx] = player
-	gs.moves = append(gs.moves, idx)
-	gs.updateTerminalState()
+	gs.play(idx)
 	return nil
 }
 
+// UndoMove takes back the most recent move, returning the square letter that
+// was removed. Winner and draw flags are recomputed, so undoing the move that
+// ended the game resumes play.
+func (gs *GameState) UndoMove() (string, error) {
+	if gs.n == 0 {
+		return "", fmt.Errorf("no moves to undo")
+	}
+	gs.n--
+	idx := gs.moves[gs.n]
+	gs.moves[gs.n] = 0
+	mask := ^(uint16(1) << idx)
+	gs.x &= mask
+	gs.o &= mask
+	gs.winner = 0
+	gs.draw = false
+	gs.updateTerminalState()
+	return squareStrings[idx], nil
+}
+
 // updateTerminalState updates winner/draw flags after a move or parsing.
 func (gs *GameState) updateTerminalState() {
 	if gs.winner != 0 || gs.draw {
 		return
 	}
 	// Check win
-	for _, line := range winLines {
-		a, b, c := line[0], line[1], line[2]
-		if gs.board[a] != 0 && gs.board[a] == gs.board[b] && gs.board[a] == gs.board[c] {
-			gs.winner = gs.board[a]
-			return
-		}
+	switch {
+	case isWinning[gs.x]:
+		gs.winner = 'X'
+		return
+	case isWinning[gs.o]:
+		gs.winner = 'O'
+		return
 	}
 	// Check draw
-	if len(gs.moves) == 9 {
+	if gs.n == 9 {
 		gs.draw = true
 	}
 }
@@ -224,7 +314,7 @@ func (gs *GameState) BoardString() string {
 		b.WriteString(fmt.Sprintf("%d |", r+1))
 		for c := 0; c < 3; c++ {
 			idx := r*3 + c
-			ch := gs.board[idx]
+			ch := gs.at(idx)
 			if ch == 0 {
 				ch = ' '
 			}
@@ -1,6 +1,9 @@
 package ticktacktoe
 
-import "testing"
+import (
+	"math/bits"
+	"testing"
+)
 
 func TestParseAndSerializeEmpty(t *testing.T) {
 	gs, err := GameStateFromString("")
@@ -92,6 +95,14 @@ func TestListValidMoves(t *testing.T) {
 			t.Fatalf("A should not be valid")
 		}
 	}
+	if len(moves) != bits.OnesCount16(gs.LegalMoveMask()) {
+		t.Fatalf("expected one move per bit of the legal move mask, got %v", moves)
+	}
+
+	won, _ := GameStateFromString("ADBEC")
+	if moves := won.ListValidMoves(); moves != nil {
+		t.Fatalf("expected no moves once the game is over, got %v", moves)
+	}
 }
 
 func TestBoardString(t *testing.T) {
@@ -133,3 +144,73 @@ func TestGridAddressTranslation(t *testing.T) {
 		}
 	}
 }
+
+func TestCloneIsIndependent(t *testing.T) {
+	gs, _ := GameStateFromString("AE")
+	cp := gs.Clone()
+	if err := cp.ApplyMove("I"); err != nil {
+		t.Fatal(err)
+	}
+	if gs.ToString() != "AE" || cp.ToString() != "AEI" {
+		t.Fatalf("clone aliased original: %s / %s", gs.ToString(), cp.ToString())
+	}
+}
+
+func TestLegalMoveMask(t *testing.T) {
+	gs, _ := GameStateFromString("AE")
+	if got, want := gs.LegalMoveMask(), fullBoard&^(1<<0|1<<4); got != want {
+		t.Fatalf("expected mask %09b got %09b", want, got)
+	}
+	won, _ := GameStateFromString("ADBEC") // X wins top row
+	if won.LegalMoveMask() != 0 {
+		t.Fatalf("expected no legal moves after a win")
+	}
+}
+
+func BenchmarkListValidMoves(b *testing.B) {
+	gs, _ := GameStateFromString("AEI")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = gs.ListValidMoves()
+	}
+}
+
+// BenchmarkPlayout measures copying a position and playing it out, the inner
+// loop of self-play and solver evaluations.
+func BenchmarkPlayout(b *testing.B) {
+	start, _ := GameStateFromString("E")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		gs := *start
+		for free := gs.LegalMoveMask(); free != 0; free = gs.LegalMoveMask() {
+			gs.play(bits.TrailingZeros16(free))
+		}
+	}
+}
+
+func TestUndoMove(t *testing.T) {
+	gs, _ := GameStateFromString("ADBEC") // X wins top row
+	sq, err := gs.UndoMove()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sq != "C" {
+		t.Fatalf("expected C undone, got %s", sq)
+	}
+	if gs.Winner() != 0 || gs.PlayerToMove() != 'X' || gs.ToString() != "ADBE" {
+		t.Fatalf("expected game resumed with X to move, got %s winner %c", gs.ToString(), gs.Winner())
+	}
+	if err := gs.ApplyMove("C"); err != nil || gs.Winner() != 'X' {
+		t.Fatalf("expected replaying C to win again (%v)", err)
+	}
+
+	draw, _ := GameStateFromString("ABCFDGEIH")
+	if _, err := draw.UndoMove(); err != nil || draw.IsDraw() {
+		t.Fatalf("expected draw cleared after undo (%v)", err)
+	}
+
+	empty := NewGameState()
+	if _, err := empty.UndoMove(); err == nil {
+		t.Fatalf("expected error undoing empty game")
+	}
+}
@@ -0,0 +1,81 @@
+package userdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileStore keeps values as files under a directory, one subdirectory per
+// user, so that a local server keeps games in progress across restarts.
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore returns a store rooted at dir, which is created on first write.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// path escapes user IDs and keys so that neither can leave the directory nor
+// contain characters that some file systems reject, such as ':'.
+func (f *FileStore) path(userID, key string) string {
+	return filepath.Join(f.dir, url.QueryEscape(userID), url.QueryEscape(key))
+}
+
+func (f *FileStore) GetUserData(ctx context.Context, userID, key string) ([]byte, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	b, err := os.ReadFile(f.path(userID, key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("error reading user data: %w", err)
+	}
+	return b, true, nil
+}
+
+// PutUserData writes the value to a temporary file and renames it into
+// place, so that a crash never leaves a partial value behind.
+func (f *FileStore) PutUserData(ctx context.Context, userID, key string, value []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p := f.path(userID, key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return fmt.Errorf("error creating user data directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("error writing user data: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(value); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing user data: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing user data: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return fmt.Errorf("error writing user data: %w", err)
+	}
+	return nil
+}
+
+func (f *FileStore) DeleteUserData(ctx context.Context, userID, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := os.Remove(f.path(userID, key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error deleting user data: %w", err)
+	}
+	return nil
+}
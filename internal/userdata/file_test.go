@@ -0,0 +1,49 @@
+package userdata
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := NewFileStore(dir)
+
+	if _, found, err := s.GetUserData(ctx, "alice", "game:1"); err != nil || found {
+		t.Fatalf("expected no value, got found=%v err=%v", found, err)
+	}
+	if err := s.PutUserData(ctx, "alice", "game:1", []byte("first")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.PutUserData(ctx, "alice", "game:1", []byte("second")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.PutUserData(ctx, "../bob", "game:1", []byte("other")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new store over the same directory sees the values, as after a restart.
+	s = NewFileStore(dir)
+	if v, found, err := s.GetUserData(ctx, "alice", "game:1"); err != nil || !found || string(v) != "second" {
+		t.Fatalf("expected the latest value, got %q found=%v err=%v", v, found, err)
+	}
+	if v, _, _ := s.GetUserData(ctx, "../bob", "game:1"); string(v) != "other" {
+		t.Fatalf("expected users to be kept apart, got %q", v)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("expected one directory per user inside the store, got %v (%v)", entries, err)
+	}
+
+	if err := s.DeleteUserData(ctx, "alice", "game:1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteUserData(ctx, "alice", "game:1"); err != nil {
+		t.Fatalf("expected deleting a missing key to succeed, got %v", err)
+	}
+	if _, found, _ := s.GetUserData(ctx, "alice", "game:1"); found {
+		t.Fatal("expected the value to be deleted")
+	}
+}
@@ -0,0 +1,46 @@
+package userdata
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryStore is an in-process Store. Values are lost when the process exits.
+type MemoryStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{values: make(map[string][]byte)}
+}
+
+func memoryKey(userID, key string) string {
+	return userID + "\x00" + key
+}
+
+func (m *MemoryStore) GetUserData(ctx context.Context, userID, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.values[memoryKey(userID, key)]
+	if !ok {
+		return nil, false, nil
+	}
+	return append([]byte(nil), v...), true, nil
+}
+
+func (m *MemoryStore) PutUserData(ctx context.Context, userID, key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[memoryKey(userID, key)] = append([]byte(nil), value...)
+	return nil
+}
+
+func (m *MemoryStore) DeleteUserData(ctx context.Context, userID, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.values, memoryKey(userID, key))
+	return nil
+}
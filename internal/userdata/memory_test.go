@@ -0,0 +1,43 @@
+package userdata
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+
+	if _, found, err := s.GetUserData(ctx, "alice", "game:1"); err != nil || found {
+		t.Fatalf("expected no value, got found=%v err=%v", found, err)
+	}
+	value := []byte("first")
+	if err := s.PutUserData(ctx, "alice", "game:1", value); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.PutUserData(ctx, "bob", "game:1", []byte("other")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The store keeps its own copy, so callers may reuse their buffers.
+	value[0] = 'F'
+	v, found, err := s.GetUserData(ctx, "alice", "game:1")
+	if err != nil || !found || string(v) != "first" {
+		t.Fatalf("expected the stored value, got %q found=%v err=%v", v, found, err)
+	}
+	v[0] = 'F'
+	if v, _, _ := s.GetUserData(ctx, "alice", "game:1"); string(v) != "first" {
+		t.Fatalf("expected reads to return a copy, got %q", v)
+	}
+	if v, _, _ := s.GetUserData(ctx, "bob", "game:1"); string(v) != "other" {
+		t.Fatalf("expected users to be kept apart, got %q", v)
+	}
+
+	if err := s.DeleteUserData(ctx, "alice", "game:1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := s.GetUserData(ctx, "alice", "game:1"); found {
+		t.Fatal("expected the value to be deleted")
+	}
+}
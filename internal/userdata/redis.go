@@ -0,0 +1,61 @@
+package userdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore keeps user data in Redis.
+type RedisStore struct {
+	client    *redis.Client
+	keyPrefix string
+	ttl       time.Duration
+}
+
+var _ Store = (*RedisStore)(nil)
+
+// NewRedisStore returns a Store backed by the given client. Keys are
+// namespaced under keyPrefix so the store can share a database with the
+// session host. Every write refreshes the key's expiry to ttl; zero keeps
+// values forever.
+func NewRedisStore(client *redis.Client, keyPrefix string, ttl time.Duration) *RedisStore {
+	return &RedisStore{client: client, keyPrefix: keyPrefix, ttl: ttl}
+}
+
+// userKey escapes the user ID, which comes from the identity provider and
+// may contain the ':' separator. PathEscape leaves ':' alone, so it is
+// escaped separately; other IDs keep the keys they always had.
+func (r *RedisStore) userKey(userID, key string) string {
+	return r.keyPrefix + "user:" + strings.ReplaceAll(url.PathEscape(userID), ":", "%3A") + ":" + key
+}
+
+func (r *RedisStore) GetUserData(ctx context.Context, userID, key string) ([]byte, bool, error) {
+	b, err := r.client.Get(ctx, r.userKey(userID, key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("error loading user data: %w", err)
+	}
+	return b, true, nil
+}
+
+func (r *RedisStore) PutUserData(ctx context.Context, userID, key string, value []byte) error {
+	if err := r.client.Set(ctx, r.userKey(userID, key), value, r.ttl).Err(); err != nil {
+		return fmt.Errorf("error storing user data: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisStore) DeleteUserData(ctx context.Context, userID, key string) error {
+	if err := r.client.Del(ctx, r.userKey(userID, key)).Err(); err != nil {
+		return fmt.Errorf("error deleting user data: %w", err)
+	}
+	return nil
+}
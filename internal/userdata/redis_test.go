@@ -0,0 +1,84 @@
+package userdata
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRedisStore(t *testing.T, ttl time.Duration) (*RedisStore, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisStore(client, "test:", ttl), mr
+}
+
+func TestRedisStore(t *testing.T) {
+	ctx := context.Background()
+	s, mr := newTestRedisStore(t, 0)
+
+	if _, found, err := s.GetUserData(ctx, "alice", "game:1"); err != nil || found {
+		t.Fatalf("expected no value, got found=%v err=%v", found, err)
+	}
+	if err := s.PutUserData(ctx, "alice", "game:1", []byte("first")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.PutUserData(ctx, "alice:game", "1", []byte("other")); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, found, err := s.GetUserData(ctx, "alice", "game:1"); err != nil || !found || string(v) != "first" {
+		t.Fatalf("expected the stored value, got %q found=%v err=%v", v, found, err)
+	}
+	// The user ID is escaped, so a ':' in it cannot collide with a key.
+	if v, _, _ := s.GetUserData(ctx, "alice:game", "1"); string(v) != "other" {
+		t.Fatalf("expected users to be kept apart, got %q", v)
+	}
+	if !mr.Exists("test:user:alice:game:1") {
+		t.Fatalf("expected keys under the prefix, got %v", mr.Keys())
+	}
+
+	// Without a ttl values are kept forever.
+	mr.FastForward(365 * 24 * time.Hour)
+	if _, found, _ := s.GetUserData(ctx, "alice", "game:1"); !found {
+		t.Fatal("expected the value to be kept without a ttl")
+	}
+
+	if err := s.DeleteUserData(ctx, "alice", "game:1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteUserData(ctx, "alice", "game:1"); err != nil {
+		t.Fatalf("expected deleting a missing key to succeed, got %v", err)
+	}
+	if _, found, _ := s.GetUserData(ctx, "alice", "game:1"); found {
+		t.Fatal("expected the value to be deleted")
+	}
+}
+
+func TestRedisStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	s, mr := newTestRedisStore(t, time.Hour)
+
+	if err := s.PutUserData(ctx, "alice", "game:1", []byte("first")); err != nil {
+		t.Fatal(err)
+	}
+	mr.FastForward(45 * time.Minute)
+
+	// Every write refreshes the expiry.
+	if err := s.PutUserData(ctx, "alice", "game:1", []byte("second")); err != nil {
+		t.Fatal(err)
+	}
+	mr.FastForward(45 * time.Minute)
+	if v, found, _ := s.GetUserData(ctx, "alice", "game:1"); !found || string(v) != "second" {
+		t.Fatalf("expected the rewritten value to outlive the first ttl, got %q found=%v", v, found)
+	}
+
+	mr.FastForward(30 * time.Minute)
+	if _, found, err := s.GetUserData(ctx, "alice", "game:1"); err != nil || found {
+		t.Fatalf("expected the value to expire, got found=%v err=%v", found, err)
+	}
+}
@@ -0,0 +1,15 @@
+// Package userdata keeps small per-user values, such as games in progress,
+// beyond the lifetime of any one MCP session so that users can pick up where
+// they left off after reconnecting or switching hosts.
+package userdata
+
+import "context"
+
+// Store holds opaque values by user and key. Implementations MUST be safe for
+// concurrent use.
+type Store interface {
+	// GetUserData returns (nil, false, nil) when the key is not set.
+	GetUserData(ctx context.Context, userID, key string) ([]byte, bool, error)
+	PutUserData(ctx context.Context, userID, key string, value []byte) error
+	DeleteUserData(ctx context.Context, userID, key string) error
+}
@@ -0,0 +1,163 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+	"sync"
+
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Social card layout, sized for the 1.91:1 ratio expected by Open Graph and
+// Twitter summary_large_image cards.
+const (
+	cardWidth      = 1200
+	cardHeight     = 630
+	cardTextLeft   = 540
+	cardTextRight  = 1140
+	cardQuoteLines = 4
+)
+
+var (
+	cardBackground = color.RGBA{0xf6, 0xf8, 0xfa, 0xff}
+	cardTitleColor = color.RGBA{0x57, 0x60, 0x6a, 0xff}
+	cardTextColor  = color.RGBA{0x1f, 0x23, 0x28, 0xff}
+)
+
+type cardFonts struct {
+	title, headline, quote font.Face
+}
+
+var (
+	cardFontsOnce sync.Once
+	cardBold      *opentype.Font
+	cardItalic    *opentype.Font
+	cardFontsErr  error
+)
+
+// loadCardFonts parses the embedded Go fonts once per process and returns
+// new faces of them. Parsed fonts are safe to share, but faces keep scratch
+// buffers, so every render needs its own.
+func loadCardFonts() (cardFonts, error) {
+	cardFontsOnce.Do(func() {
+		if cardBold, cardFontsErr = opentype.Parse(gobold.TTF); cardFontsErr != nil {
+			cardFontsErr = fmt.Errorf("error parsing font: %w", cardFontsErr)
+			return
+		}
+		if cardItalic, cardFontsErr = opentype.Parse(goitalic.TTF); cardFontsErr != nil {
+			cardFontsErr = fmt.Errorf("error parsing font: %w", cardFontsErr)
+		}
+	})
+	if cardFontsErr != nil {
+		return cardFonts{}, cardFontsErr
+	}
+
+	var err error
+	face := func(f *opentype.Font, size float64) font.Face {
+		if err != nil {
+			return nil
+		}
+		var fc font.Face
+		if fc, err = opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull}); err != nil {
+			err = fmt.Errorf("error creating font face: %w", err)
+		}
+		return fc
+	}
+	fonts := cardFonts{
+		title:    face(cardBold, 28),
+		headline: face(cardBold, 52),
+		quote:    face(cardItalic, 34),
+	}
+	return fonts, err
+}
+
+// renderCard draws the social card for a finished game: the final board on
+// the left, with the outcome and the last heckle alongside it.
+func renderCard(gs *ticktacktoe.GameState, headline, heckle string) ([]byte, error) {
+	fonts, err := loadCardFonts()
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, cardWidth, cardHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(cardBackground), image.Point{}, draw.Src)
+
+	board := gs.BoardImage()
+	bw, bh := board.Bounds().Dx(), board.Bounds().Dy()
+	at := image.Pt((cardTextLeft-bw)/2, (cardHeight-bh)/2)
+	draw.Draw(img, image.Rectangle{Min: at, Max: at.Add(image.Pt(bw, bh))}, board, image.Point{}, draw.Src)
+
+	width := cardTextRight - cardTextLeft
+	y := 120
+	y = drawLines(img, fonts.title, cardTitleColor, []string{"TIC-TAC-TURING"}, y)
+	y += 30
+	y = drawLines(img, fonts.headline, cardTextColor, wrapText(fonts.headline, headline, width, 3), y)
+	if heckle != "" {
+		y += 30
+		drawLines(img, fonts.quote, cardTitleColor, wrapText(fonts.quote, "“"+heckle+"”", width, cardQuoteLines), y)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// drawLines draws each line starting at baseline y and returns the baseline
+// following the last line.
+func drawLines(img draw.Image, face font.Face, c color.Color, lines []string, y int) int {
+	lineHeight := face.Metrics().Height.Ceil()
+	d := &font.Drawer{Dst: img, Src: image.NewUniform(c), Face: face}
+	for _, line := range lines {
+		d.Dot = fixed.P(cardTextLeft, y)
+		d.DrawString(line)
+		y += lineHeight
+	}
+	return y
+}
+
+// wrapText greedily breaks s into lines no wider than width, truncating with
+// an ellipsis once maxLines is reached.
+func wrapText(face font.Face, s string, width, maxLines int) []string {
+	fits := func(line string) bool { return font.MeasureString(face, line).Ceil() <= width }
+
+	var lines []string
+	var cur string
+	for _, word := range strings.Fields(s) {
+		next := word
+		if cur != "" {
+			next = cur + " " + word
+		}
+		if fits(next) || cur == "" {
+			cur = next
+			continue
+		}
+		lines = append(lines, cur)
+		cur = word
+	}
+	if cur != "" {
+		lines = append(lines, cur)
+	}
+
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		last := lines[maxLines-1] + "…"
+		for !fits(last) && len(last) > len("…") {
+			r := []rune(strings.TrimSuffix(last, "…"))
+			last = strings.TrimSpace(string(r[:len(r)-1])) + "…"
+		}
+		lines[maxLines-1] = last
+	}
+	return lines
+}
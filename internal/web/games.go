@@ -0,0 +1,232 @@
+package web
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/ggoodman/tic-tac-turing/internal/archive"
+	"github.com/ggoodman/tic-tac-turing/internal/pseudonym"
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+	"github.com/ggoodman/tic-tac-turing/web/content"
+)
+
+// GamePages serves the shareable page and social card image of archived games.
+type GamePages struct {
+	games     archive.Store
+	names     *pseudonym.Namer
+	publicUrl string
+}
+
+// NewGamePages returns handlers for games stored in games. publicUrl is used
+// to build the absolute URLs required by Open Graph and Twitter card tags, and
+// names names the players in downloaded records.
+func NewGamePages(games archive.Store, names *pseudonym.Namer, publicUrl string) *GamePages {
+	return &GamePages{games: games, names: names, publicUrl: strings.TrimSuffix(publicUrl, "/")}
+}
+
+// loadGame resolves the {id} path value to an archived game and its replayed
+// state, writing an error response and returning ok=false on failure.
+func (p *GamePages) loadGame(w http.ResponseWriter, r *http.Request) (*archive.Game, *ticktacktoe.GameState, bool) {
+	id := r.PathValue("id")
+	g, found, err := p.games.GetGame(r.Context(), id)
+	if err != nil {
+		log.Printf("error loading game %s: %v", id, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return nil, nil, false
+	}
+	if !found {
+		http.NotFound(w, r)
+		return nil, nil, false
+	}
+	gs, err := g.State()
+	if err != nil {
+		log.Printf("error replaying game %s: %v", id, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return nil, nil, false
+	}
+	return g, gs, true
+}
+
+// GameHandler serves the HTML page of a finished game with Open Graph and
+// Twitter card metadata pointing at CardHandler.
+func (p *GamePages) GameHandler(w http.ResponseWriter, r *http.Request) {
+	g, gs, ok := p.loadGame(w, r)
+	if !ok {
+		return
+	}
+
+	headline := outcomeHeadline(g, gs)
+	description := headline
+	if heckle := g.LastHeckle(); heckle != "" {
+		description += " Final heckle: “" + truncate(heckle, 160) + "”"
+	}
+
+	header, main := gameMarkdown(g, gs)
+	page, err := content.RenderGenerated(header, main, content.Meta{
+		Title:       "Tick-Tack-Turing: " + headline,
+		Description: description,
+		URL:         p.publicUrl + "/games/" + g.ID,
+		Image:       p.publicUrl + "/games/" + g.ID + "/card.png",
+		ImageAlt:    "Final board of a Tic-Tac-Turing game. " + headline,
+	})
+	if err != nil {
+		log.Printf("error rendering game %s: %v", g.ID, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(page.HTML)
+}
+
+// CardHandler serves the social card PNG of a finished game. Archived games
+// never change, so the image is cacheable.
+func (p *GamePages) CardHandler(w http.ResponseWriter, r *http.Request) {
+	g, gs, ok := p.loadGame(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := renderCard(gs, outcomeHeadline(g, gs), g.LastHeckle())
+	if err != nil {
+		log.Printf("error rendering card for game %s: %v", g.ID, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// RecordHandler serves a finished game in the portable record format so it
+// can be downloaded for analysis or imported elsewhere.
+func (p *GamePages) RecordHandler(w http.ResponseWriter, r *http.Request) {
+	g, _, ok := p.loadGame(w, r)
+	if !ok {
+		return
+	}
+
+	rec, err := g.Record(p.names)
+	if err != nil {
+		log.Printf("error building record for game %s: %v", g.ID, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"tic-tac-turing-%s.txt\"", g.ID))
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(rec.String()))
+}
+
+// outcomeHeadline describes the result of a game from the human's perspective.
+func outcomeHeadline(g *archive.Game, gs *ticktacktoe.GameState) string {
+	switch g.Outcome(gs) {
+	case ticktacktoe.ResultXWins:
+		return "A human beat the champion!"
+	case ticktacktoe.ResultOWins:
+		if g.Termination == archive.TerminationResignation {
+			return "The human resigned. The champion remains undefeated."
+		}
+		return "The champion remains undefeated."
+	case ticktacktoe.ResultDraw:
+		if g.Termination == archive.TerminationAgreement {
+			return "A draw by agreement. The champion holds on."
+		}
+		return "A draw. The champion holds on."
+	default:
+		if g.Termination == archive.TerminationAbandoned {
+			return "This game was abandoned."
+		}
+		return "This game was never finished."
+	}
+}
+
+// gameMarkdown builds the markdown source of the header and main content of
+// a game page. Heckles come from users, so they are escaped to render as
+// literal text.
+func gameMarkdown(g *archive.Game, gs *ticktacktoe.GameState) (header, main []byte) {
+	header = []byte("# Tic‑Tac‑Turing\n\n" + outcomeHeadline(g, gs) + "\n")
+
+	var b strings.Builder
+	b.WriteString("## Final board\n\n```text\n")
+	b.WriteString(gs.BoardString())
+	b.WriteString("```\n\n")
+
+	if heckle := g.LastHeckle(); heckle != "" {
+		b.WriteString("## The last heckle\n\n")
+		b.WriteString(quoteMarkdown(heckle))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Moves\n\n")
+	for i, sq := range gs.ToString() {
+		grid, _ := ticktacktoe.SquareToGrid(string(sq))
+		player := "X"
+		if i%2 == 1 {
+			player = "O"
+		}
+		if i < g.SetUpPlies {
+			fmt.Fprintf(&b, "%d. %s set up on %s\n", i+1, player, grid)
+			continue
+		}
+		fmt.Fprintf(&b, "%d. %s played %s\n", i+1, player, grid)
+		if i%2 == 0 && i/2 < len(g.Heckles) && g.Heckles[i/2] != "" {
+			b.WriteString("\n" + indent(quoteMarkdown(g.Heckles[i/2]), "   ") + "\n")
+		}
+	}
+
+	review := g.Review
+	if review == nil {
+		review, _ = archive.NewReview(g)
+	}
+	if review != nil && len(g.Moves) > 0 {
+		// The review quotes heckles; escape them without touching the
+		// review's own markdown.
+		escaped := *review
+		escaped.Blunders = slices.Clone(review.Blunders)
+		for i := range escaped.Blunders {
+			escaped.Blunders[i].Heckle = content.EscapeMarkdown(escaped.Blunders[i].Heckle)
+		}
+		b.WriteString("\n" + escaped.String())
+	}
+
+	fmt.Fprintf(&b, "\n[Download the game record](/games/%s/record). Think you can do better? [Challenge the champion](/).\n", g.ID)
+	return header, []byte(b.String())
+}
+
+// quoteMarkdown escapes s and renders it as a markdown block quote.
+func quoteMarkdown(s string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(content.EscapeMarkdown(s), "\n") {
+		b.WriteString("> " + line + "\n")
+	}
+	return b.String()
+}
+
+// indent prefixes every line of s, nesting markdown blocks under list items.
+func indent(s, prefix string) string {
+	lines := strings.SplitAfter(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:n-1])) + "…"
+}
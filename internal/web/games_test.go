@@ -0,0 +1,94 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ggoodman/tic-tac-turing/internal/archive"
+	"github.com/ggoodman/tic-tac-turing/internal/pseudonym"
+	ticktacktoe "github.com/ggoodman/tic-tac-turing/internal/tic_tac_toe"
+)
+
+func TestGamePageEscapesHeckles(t *testing.T) {
+	var moves string
+	for _, grid := range []string{"B2", "B1"} {
+		sq, err := ticktacktoe.GridToSquare(grid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		moves += sq
+	}
+	games := archive.NewMemoryStore()
+	g := &archive.Game{
+		ID:          "malicious",
+		Moves:       moves,
+		Heckles:     []string{"[x](javascript:alert(document.cookie)) <script>alert(1)</script> ![](https://evil.example/pixel.png)"},
+		Result:      ticktacktoe.ResultOWins,
+		Termination: archive.TerminationResignation,
+	}
+	if err := games.PutGame(context.Background(), g); err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /games/{id}", NewGamePages(games, nil, "https://example.com").GameHandler)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/games/malicious", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	body := rec.Body.String()
+	for _, bad := range []string{"href=\"javascript:", "<script>", "<img"} {
+		if strings.Contains(body, bad) {
+			t.Errorf("expected the page not to contain %q, got:\n%s", bad, body)
+		}
+	}
+	for _, want := range []string{"<header>", "<main>", "[x](javascript:alert(document.cookie))", "&lt;script&gt;"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected the page to contain %q, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestCardsRenderConcurrently(t *testing.T) {
+	gs := ticktacktoe.NewGameState()
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			if _, err := renderCard(gs, "A draw. The champion holds on.", "Is that the best you can do?"); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func TestRecordNamesPlayerByPseudonym(t *testing.T) {
+	games := archive.NewMemoryStore()
+	g := &archive.Game{ID: "named", UserID: "google-oauth2|1234567890", Result: ticktacktoe.ResultOWins, Termination: archive.TerminationResignation}
+	if err := games.PutGame(context.Background(), g); err != nil {
+		t.Fatal(err)
+	}
+	names := pseudonym.New([]byte("secret"))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /games/{id}/record", NewGamePages(games, names, "https://example.com").RecordHandler)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/games/named/record", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	body := rec.Body.String()
+	if strings.Contains(body, g.UserID) {
+		t.Errorf("expected the record not to contain the user ID, got:\n%s", body)
+	}
+	if want := `[X "` + names.Name(g.UserID) + `"]`; !strings.Contains(body, want) {
+		t.Errorf("expected the record to contain %s, got:\n%s", want, body)
+	}
+}
@@ -0,0 +1,93 @@
+package web
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/ggoodman/tic-tac-turing/internal/pseudonym"
+	"github.com/ggoodman/tic-tac-turing/internal/rating"
+	"github.com/ggoodman/tic-tac-turing/web/content"
+)
+
+// ratingsPageSize is how many players of each kind the ratings page lists.
+const ratingsPageSize = 50
+
+// RatingsPage serves the public leaderboards of players and champions.
+type RatingsPage struct {
+	ratings   rating.Store
+	names     *pseudonym.Namer
+	publicUrl string
+}
+
+// NewRatingsPage returns a handler for the leaderboards in ratings, naming
+// players by the pseudonyms of names.
+func NewRatingsPage(ratings rating.Store, names *pseudonym.Namer, publicUrl string) *RatingsPage {
+	return &RatingsPage{ratings: ratings, names: names, publicUrl: strings.TrimSuffix(publicUrl, "/")}
+}
+
+// Handler serves the ratings page.
+func (p *RatingsPage) Handler(w http.ResponseWriter, r *http.Request) {
+	players, err := p.ratings.Leaderboard(r.Context(), rating.KindUser, ratingsPageSize)
+	if err != nil {
+		log.Printf("error loading player ratings: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	champions, err := p.ratings.Leaderboard(r.Context(), rating.KindChampion, ratingsPageSize)
+	if err != nil {
+		log.Printf("error loading champion ratings: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	// The page is public: show users by pseudonym, never by user ID.
+	for i := range players {
+		players[i].Player = p.names.Name(players[i].Player)
+	}
+	header, main := ratingsMarkdown(players, champions)
+	page, err := content.RenderGenerated(header, main, content.Meta{
+		Title:       "Tick-Tack-Turing ratings",
+		Description: "Elo ratings of the humans who challenged the champion, and of the champions they faced.",
+		URL:         p.publicUrl + "/ratings",
+	})
+	if err != nil {
+		log.Printf("error rendering ratings: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	w.WriteHeader(http.StatusOK)
+	w.Write(page.HTML)
+}
+
+// ratingsMarkdown builds the markdown source of the header and main content
+// of the ratings page. Champion names come from the host's model, so they are
+// escaped.
+func ratingsMarkdown(players, champions []rating.Rating) (header, main []byte) {
+	header = fmt.Appendf(nil, "# Tic‑Tac‑Turing ratings\n\nEveryone starts at %.0f. Beating a highly rated champion earns more than beating a weak one.\n", rating.Initial)
+
+	var b strings.Builder
+
+	writeTable := func(title, who string, ratings []rating.Rating) {
+		fmt.Fprintf(&b, "## %s\n\n", title)
+		if len(ratings) == 0 {
+			b.WriteString("Nobody is rated yet.\n\n")
+			return
+		}
+		fmt.Fprintf(&b, "| # | %s | Rating | Won | Drawn | Lost |\n|---|---|---|---|---|---|\n", who)
+		for i, r := range ratings {
+			name := content.EscapeMarkdown(r.Player)
+			fmt.Fprintf(&b, "| %d | %s | %.0f | %d | %d | %d |\n", i+1, name, r.Rating, r.Wins, r.Draws, r.Losses)
+		}
+		b.WriteString("\n")
+	}
+	writeTable("Players", "Player", players)
+	writeTable("Champions", "Persona / model", champions)
+
+	b.WriteString("Think you can climb the table? [Challenge the champion](/).\n")
+	return header, []byte(b.String())
+}
@@ -0,0 +1,17 @@
+package content
+
+import "strings"
+
+// EscapeMarkdown backslash-escapes every ASCII punctuation character of s so
+// that it renders as literal text wherever it is placed in markdown: user
+// text can then never form links, images, emphasis or HTML.
+func EscapeMarkdown(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r < 0x80 && strings.ContainsRune("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -4,11 +4,14 @@ package content
 import (
 	"bytes"
 	"fmt"
+	"html/template"
+	"strings"
 
 	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
 	"github.com/yuin/goldmark"
 	highlighting "github.com/yuin/goldmark-highlighting/v2"
 	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/renderer/html"
 	"go.abhg.dev/goldmark/mermaid"
 )
@@ -19,20 +22,64 @@ type Page struct {
 	HTML []byte
 }
 
+// Meta describes the document-level metadata of a rendered page. Image and URL
+// are optional; when Image is set, Open Graph and Twitter card tags are emitted
+// so the page unfurls with a preview when shared.
+type Meta struct {
+	Title       string
+	Description string
+	URL         string
+	Image       string
+	ImageAlt    string
+}
+
+// DefaultMeta is the metadata used for the home page.
+var DefaultMeta = Meta{
+	Title:       "Tick-Tack-Turing",
+	Description: "Tick-Tack-Turing: a playful Model Context Protocol (MCP) powered twist on tic-tac-toe where a human challenges an LLM champion.",
+}
+
+// headHTML renders the <title> and meta tags for m, escaping all values.
+func (m Meta) headHTML() string {
+	var b strings.Builder
+	tag := func(attr, key, value string) {
+		if value == "" {
+			return
+		}
+		fmt.Fprintf(&b, "  <meta %s=\"%s\" content=\"%s\">\n", attr, key, template.HTMLEscapeString(value))
+	}
+	fmt.Fprintf(&b, "  <title>%s</title>\n", template.HTMLEscapeString(m.Title))
+	tag("name", "description", m.Description)
+	if m.Image != "" {
+		tag("property", "og:type", "website")
+		tag("property", "og:site_name", DefaultMeta.Title)
+		tag("property", "og:title", m.Title)
+		tag("property", "og:description", m.Description)
+		tag("property", "og:url", m.URL)
+		tag("property", "og:image", m.Image)
+		tag("property", "og:image:alt", m.ImageAlt)
+		tag("name", "twitter:card", "summary_large_image")
+		tag("name", "twitter:title", m.Title)
+		tag("name", "twitter:description", m.Description)
+		tag("name", "twitter:image", m.Image)
+		tag("name", "twitter:image:alt", m.ImageAlt)
+	}
+	return b.String()
+}
+
 // htmlTemplate wraps rendered markdown content in a minimal HTML document.
+// The first verb receives the head metadata, the second the page body.
 const htmlTemplate = `<!DOCTYPE html>
 <html lang="en">
 <head>
   <meta charset="UTF-8">
   <meta name="viewport" content="width=device-width, initial-scale=1">
-  <title>Tick-Tack-Turing</title>
-  <meta name="description" content="Tick-Tack-Turing: a playful Model Context Protocol (MCP) powered twist on tic-tac-toe where a human challenges an LLM champion.">
-	<link rel="stylesheet" href="styles.css">
-	<link rel="icon" type="image/png" sizes="32x32" href="favicon-32x32.png">
-	<link rel="icon" type="image/png" sizes="16x16" href="favicon-16x16.png">
-	<link rel="apple-touch-icon" sizes="180x180" href="apple-touch-icon.png">
-	<link rel="manifest" href="site.webmanifest">
-	<link rel="shortcut icon" href="favicon.ico">
+%s	<link rel="stylesheet" href="/styles.css">
+	<link rel="icon" type="image/png" sizes="32x32" href="/favicon-32x32.png">
+	<link rel="icon" type="image/png" sizes="16x16" href="/favicon-16x16.png">
+	<link rel="apple-touch-icon" sizes="180x180" href="/apple-touch-icon.png">
+	<link rel="manifest" href="/site.webmanifest">
+	<link rel="shortcut icon" href="/favicon.ico">
 </head>
 <body>
 %s
@@ -48,8 +95,65 @@ func MustLoadHome() *Page {
 		panic(fmt.Sprintf("failed to read home.md: %v", err))
 	}
 
-	// Configure goldmark with GitHub-flavored Markdown extensions
-	md := goldmark.New(
+	page, err := Render(raw, DefaultMeta)
+	if err != nil {
+		panic(fmt.Sprintf("failed to render home.md: %v", err))
+	}
+
+	return page
+}
+
+// Render converts markdown to a complete HTML document whose head carries the
+// supplied metadata. Raw HTML in raw is passed through, so Render is only for
+// trusted content such as the home page; see RenderGenerated.
+func Render(raw []byte, meta Meta) (*Page, error) {
+	var buf bytes.Buffer
+	if err := newMarkdown(true).Convert(raw, &buf); err != nil {
+		return nil, err
+	}
+
+	// Wrap rendered content in HTML template
+	rendered := fmt.Sprintf(htmlTemplate, meta.headHTML(), buf.String())
+
+	return &Page{
+		Raw:  raw,
+		HTML: []byte(rendered),
+	}, nil
+}
+
+// RenderGenerated converts the markdown of a page generated at request time
+// to a complete HTML document, wrapping header and main in elements of the
+// same name. Raw HTML and dangerous link destinations are dropped, so text
+// supplied by users can never become markup.
+func RenderGenerated(header, main []byte, meta Meta) (*Page, error) {
+	md := newMarkdown(false)
+	var buf bytes.Buffer
+	buf.WriteString("<header>\n")
+	if err := md.Convert(header, &buf); err != nil {
+		return nil, err
+	}
+	buf.WriteString("</header>\n<main>\n")
+	if err := md.Convert(main, &buf); err != nil {
+		return nil, err
+	}
+	buf.WriteString("</main>")
+
+	rendered := fmt.Sprintf(htmlTemplate, meta.headHTML(), buf.String())
+
+	return &Page{
+		Raw:  append(append(append([]byte{}, header...), '\n'), main...),
+		HTML: []byte(rendered),
+	}, nil
+}
+
+// newMarkdown configures goldmark with the extensions used by every page.
+// unsafe passes raw HTML in the markdown through to the output.
+func newMarkdown(unsafe bool) goldmark.Markdown {
+	var opts []renderer.Option
+	if unsafe {
+		opts = append(opts, html.WithUnsafe()) // Allow raw HTML in markdown if needed
+	}
+	return goldmark.New(
 		goldmark.WithExtensions(
 			extension.GFM,         // GitHub Flavored Markdown
 			extension.Typographer, // Smart quotes, dashes
@@ -62,21 +166,6 @@ func MustLoadHome() *Page {
 				),
 			),
 		),
-		goldmark.WithRendererOptions(
-			html.WithUnsafe(), // Allow raw HTML in markdown if needed
-		),
+		goldmark.WithRendererOptions(opts...),
 	)
-
-	var buf bytes.Buffer
-	if err := md.Convert(raw, &buf); err != nil {
-		panic(fmt.Sprintf("failed to render home.md: %v", err))
-	}
-
-	// Wrap rendered content in HTML template
-	rendered := fmt.Sprintf(htmlTemplate, buf.String())
-
-	return &Page{
-		Raw:  raw,
-		HTML: []byte(rendered),
-	}
 }